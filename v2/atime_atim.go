@@ -0,0 +1,22 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux || openbsd || dragonfly
+// +build linux openbsd dragonfly
+
+package id3v2
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time of file with stat.
+func accessTime(stat os.FileInfo) time.Time {
+	if st, ok := stat.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(int64(st.Atim.Sec), int64(st.Atim.Nsec))
+	}
+	return stat.ModTime()
+}
@@ -0,0 +1,19 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux && !openbsd && !dragonfly && !darwin && !freebsd && !netbsd && !windows
+// +build !linux,!openbsd,!dragonfly,!darwin,!freebsd,!netbsd,!windows
+
+package id3v2
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime returns the modification time of file with stat, because
+// the last access time is not available on this platform.
+func accessTime(stat os.FileInfo) time.Time {
+	return stat.ModTime()
+}
@@ -24,6 +24,14 @@ func (bw *bufWriter) EncodeAndWriteText(src string, to Encoding) {
 	bw.err = encodeWriteText(bw, src, to)
 }
 
+func (bw *bufWriter) EncodeAndWriteTextValues(values []string, to Encoding) {
+	if bw.err != nil {
+		return
+	}
+
+	bw.err = encodeWriteTextValues(bw, values, to)
+}
+
 func (bw *bufWriter) Flush() error {
 	if bw.err != nil {
 		return bw.err
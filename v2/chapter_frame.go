@@ -98,7 +98,7 @@ func parseChapterFrame(br *bufReader, version byte) (Framer, error) {
 
 	for {
 		header, err := parseFrameHeader(buf, br, synchSafe)
-		if err == io.EOF || err == errBlankFrame || err == ErrInvalidSizeFormat {
+		if err == io.EOF || err == io.ErrUnexpectedEOF || err == errBlankFrame || err == ErrInvalidSizeFormat {
 			break
 		}
 		if err != nil {
@@ -0,0 +1,131 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+// cancellingReader cancels context, when more than limit bytes are read.
+type cancellingReader struct {
+	io.Reader
+	cancel context.CancelFunc
+	limit  int
+	read   int
+}
+
+func (cr *cancellingReader) Read(p []byte) (int, error) {
+	n, err := cr.Reader.Read(p)
+	cr.read += n
+	if cr.read > cr.limit {
+		cr.cancel()
+	}
+	return n, err
+}
+
+// cancellingFile is File, which cancels context, when more than limit
+// bytes are read.
+type cancellingFile struct {
+	File
+	cr *cancellingReader
+}
+
+func (cf cancellingFile) Read(p []byte) (int, error) {
+	return cf.cr.Read(p)
+}
+
+func TestParseReaderContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseReaderContext(ctx, bytes.NewReader(makeSourceWithMusic(t)), parseOpts)
+	if err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestParseReaderContextCancelledBetweenFrames(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.SetArtist("Artist")
+	tag.SetAlbum("Album")
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel after the tag header and the first frame are read.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rd := &cancellingReader{Reader: buf, cancel: cancel, limit: tagHeaderSize + frameHeaderSize}
+
+	parsed, err := ParseReaderContext(ctx, rd, parseOpts)
+	if err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if count := parsed.Count(); count != 1 {
+		t.Errorf("Expected 1 parsed frame, got %v", count)
+	}
+}
+
+func TestSaveContextCancelledDuringCopy(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	buf := new(bytes.Buffer)
+	tagSize, err := tag.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Write(make([]byte, 512*1024))
+	source := buf.Bytes()
+
+	fs := newMemFS()
+	fs.files["song.mp3"] = source
+	file, err := fs.Open("song.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel after the first chunk of music is read.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cr := &cancellingReader{Reader: file, cancel: cancel, limit: int(tagSize) + 1}
+
+	parsed, err := ParseReader(cancellingFile{File: file, cr: cr}, Options{Parse: true, FS: fs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed.SetTitle("New title")
+	if err := parsed.SaveContext(ctx); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	if names := fs.names(); len(names) != 1 || names[0] != "song.mp3" {
+		t.Errorf("Expected only %q in FS, got %v", "song.mp3", names)
+	}
+	if !bytes.Equal(fs.files["song.mp3"], source) {
+		t.Error("Original file must be left untouched")
+	}
+}
+
+func TestOpenContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := OpenContext(ctx, mp3Path, parseOpts); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
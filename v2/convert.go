@@ -0,0 +1,293 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	// v23OnlyIDs are IDs of ID3v2.3 frames, which are removed
+	// in ID3v2.4 and have no equivalents in it. EQUA and RVAD are
+	// kept unchanged, because their data can't be converted to EQU2
+	// and RVA2 without loss.
+	v23OnlyIDs = []string{"TSIZ"}
+
+	// v24OnlyIDs are IDs of ID3v2.4 frames, which have no equivalents
+	// in ID3v2.3. Sort order frames (TSOA, TSOP, TSOT and TSST) are kept,
+	// because they're widely used in ID3v2.3 tags too.
+	v24OnlyIDs = []string{
+		"ASPI", "EQU2", "RVA2", "SEEK", "SIGN", "TDEN", "TDRL", "TDTG",
+		"TMOO", "TPRO",
+	}
+)
+
+// ConvertVersion sets given ID3v2 version to tag and converts frames
+// to the equivalents of this version:
+//
+// ID3v2.3 -> ID3v2.4: TYER, TDAT, TIME and TRDA are converted to TDRC,
+// TORY to TDOR, IPLS to TIPL and ID3v1 genre references in TCON are resolved.
+//
+// ID3v2.4 -> ID3v2.3: TDRC is converted to TYER, TDAT and TIME, TDOR to TORY,
+// TIPL and TMCL to IPLS, multiple values of text frames are joined with "/"
+// and texts encoded with UTF-8 or UTF-16BE are reencoded with UTF-16.
+//
+// Sort order frames (TSOA, TSOP, TSOT and TSST), EQUA and RVAD are kept
+// unchanged. Frames, which could not be converted, are deleted from tag and
+// their IDs are returned.
+// If version is less than 3 or greater than 4, then this method will do nothing.
+func (tag *Tag) ConvertVersion(version byte) []string {
+	if version < 3 || version > 4 {
+		return nil
+	}
+
+	tag.loadAllLazyFrames()
+
+	var unconverted []string
+	if tag.version == 3 && version == 4 {
+		unconverted = tag.convertV23ToV24()
+	} else if tag.version == 4 && version == 3 {
+		unconverted = tag.convertV24ToV23()
+	}
+
+	tag.setVersion(version)
+
+	sort.Strings(unconverted)
+	return unconverted
+}
+
+func (tag *Tag) convertV23ToV24() []string {
+	unconverted := tag.deleteExistingFrames(v23OnlyIDs)
+
+	if dateIDs := tag.existingIDs("TYER", "TDAT", "TIME", "TRDA"); len(dateIDs) > 0 {
+		encoding := tag.GetTextFrame(dateIDs[0]).Encoding
+		ts, err := tag.Timestamp("TDRC")
+		converted := err == nil && !ts.IsZero()
+		if !converted {
+			unconverted = append(unconverted, dateIDs...)
+		} else if tag.GetLastFrame("TYER") != nil && tag.GetLastFrame("TRDA") != nil {
+			// TRDA is ignored by timestamp if there is TYER.
+			unconverted = append(unconverted, "TRDA")
+		}
+		tag.deleteFrames("TYER", "TDAT", "TIME", "TRDA")
+		if converted {
+			tag.AddTextFrame("TDRC", encoding, ts.String())
+		}
+	}
+
+	if tag.GetLastFrame("TORY") != nil {
+		encoding := tag.GetTextFrame("TORY").Encoding
+		ts, err := tag.Timestamp("TDOR")
+		tag.DeleteFrames("TORY")
+		if err != nil || ts.IsZero() {
+			unconverted = append(unconverted, "TORY")
+		} else {
+			tag.AddTextFrame("TDOR", encoding, ts.String())
+		}
+	}
+
+	if f := tag.GetLastFrame("IPLS"); f != nil {
+		tag.DeleteFrames("IPLS")
+		tf, err := parseInvolvedPeopleList(f)
+		if err != nil {
+			unconverted = append(unconverted, "IPLS")
+		} else {
+			tag.AddFrame("TIPL", tf)
+		}
+	}
+
+	if tf, ok := tag.GetLastFrame("TCON").(TextFrame); ok {
+		tf.Text = formatGenres(parseGenres(tf.Text, 3), 4)
+		tag.AddFrame("TCON", tf)
+	}
+
+	return unconverted
+}
+
+func (tag *Tag) convertV24ToV23() []string {
+	unconverted := tag.deleteExistingFrames(v24OnlyIDs)
+
+	if tag.GetLastFrame("TDRC") != nil {
+		encoding := v23Encoding(tag.GetTextFrame("TDRC").Encoding)
+		ts, err := tag.Timestamp("TDRC")
+		tag.DeleteFrames("TDRC")
+		if err != nil || ts.IsZero() {
+			unconverted = append(unconverted, "TDRC")
+		} else {
+			year, date, tm := timestampToV23(ts)
+			tag.addTextFrameIfNotBlank("TYER", encoding, year)
+			tag.addTextFrameIfNotBlank("TDAT", encoding, date)
+			tag.addTextFrameIfNotBlank("TIME", encoding, tm)
+		}
+	}
+
+	if tag.GetLastFrame("TDOR") != nil {
+		encoding := v23Encoding(tag.GetTextFrame("TDOR").Encoding)
+		ts, err := tag.Timestamp("TDOR")
+		tag.DeleteFrames("TDOR")
+		if err != nil || ts.IsZero() {
+			unconverted = append(unconverted, "TDOR")
+		} else {
+			year, _, _ := timestampToV23(ts)
+			tag.addTextFrameIfNotBlank("TORY", encoding, year)
+		}
+	}
+
+	if tag.hasAnyFrame("TIPL", "TMCL") {
+		tipl, tmcl := tag.GetTextFrame("TIPL"), tag.GetTextFrame("TMCL")
+		encoding := tipl.Encoding
+		if tag.GetLastFrame("TIPL") == nil {
+			encoding = tmcl.Encoding
+		}
+		deleted := tag.existingIDs("TIPL", "TMCL")
+		tag.deleteFrames(deleted...)
+
+		values := append(tipl.Values(), tmcl.Values()...)
+		ipls, err := writeInvolvedPeopleList(v23Encoding(encoding), values)
+		if err != nil {
+			unconverted = append(unconverted, deleted...)
+		} else {
+			tag.AddFrame("IPLS", ipls)
+		}
+	}
+
+	tag.convertFrames(convertFrameToV23)
+
+	return unconverted
+}
+
+// convertFrames replaces every frame in tag with the result of convert.
+// The original data of frame is kept only if convert doesn't change it.
+func (tag *Tag) convertFrames(convert func(id string, f Framer) Framer) {
+	for id, f := range tag.frames {
+		converted := convert(id, f)
+		if tag.rawFrames[id] != nil && !reflect.DeepEqual(converted, f) {
+			delete(tag.rawFrames, id)
+		}
+		tag.frames[id] = converted
+	}
+	for id, s := range tag.sequences {
+		for i, f := range s.frames {
+			converted := convert(id, f)
+			if s.raw(i) != nil && !reflect.DeepEqual(converted, f) {
+				s.setRaw(i, nil)
+			}
+			s.frames[i] = converted
+		}
+	}
+}
+
+// convertFrameToV23 reencodes texts of f, which are not allowed in ID3v2.3,
+// and joins multiple values of text frame.
+func convertFrameToV23(id string, f Framer) Framer {
+	switch f := f.(type) {
+	case TextFrame:
+		return convertTextFrameToV23(id, f)
+	case CommentFrame:
+		f.Encoding = v23Encoding(f.Encoding)
+		return f
+	case PictureFrame:
+		f.Encoding = v23Encoding(f.Encoding)
+		return f
+	case UnsynchronisedLyricsFrame:
+		f.Encoding = v23Encoding(f.Encoding)
+		return f
+	case UserDefinedTextFrame:
+		f.Encoding = v23Encoding(f.Encoding)
+		return f
+	case ChapterFrame:
+		if f.Title != nil {
+			title := convertTextFrameToV23("TIT2", *f.Title)
+			f.Title = &title
+		}
+		if f.Description != nil {
+			description := convertTextFrameToV23("TIT3", *f.Description)
+			f.Description = &description
+		}
+		return f
+	}
+	return f
+}
+
+func convertTextFrameToV23(id string, tf TextFrame) TextFrame {
+	tf.Encoding = v23Encoding(tf.Encoding)
+	if id == "TCON" {
+		tf.Text = formatGenres(ParseGenres(tf.Text), 3)
+	} else if tf.hasMultipleValues() {
+		tf.Text = strings.Join(tf.Values(), "/")
+	}
+	return tf
+}
+
+// v23Encoding returns UTF-16 if encoding is not allowed in ID3v2.3.
+func v23Encoding(encoding Encoding) Encoding {
+	if encoding.Equals(EncodingUTF8) || encoding.Equals(EncodingUTF16BE) {
+		return EncodingUTF16
+	}
+	return encoding
+}
+
+// parseInvolvedPeopleList parses body of ID3v2.3 IPLS frame, which has
+// the same format as body of text frame with multiple values.
+func parseInvolvedPeopleList(f Framer) (TextFrame, error) {
+	buf := new(bytes.Buffer)
+	if _, err := f.WriteTo(buf); err != nil {
+		return TextFrame{}, err
+	}
+
+	tf, err := parseTextFrame(newBufReader(buf))
+	if err != nil {
+		return TextFrame{}, err
+	}
+	return tf.(TextFrame), nil
+}
+
+// writeInvolvedPeopleList creates ID3v2.3 IPLS frame with values.
+func writeInvolvedPeopleList(encoding Encoding, values []string) (UnknownFrame, error) {
+	buf := new(bytes.Buffer)
+	tf := TextFrame{Encoding: encoding, Text: strings.Join(values, textValuesSeparator)}
+	if _, err := tf.WriteTo(buf); err != nil {
+		return UnknownFrame{}, err
+	}
+	return UnknownFrame{Body: buf.Bytes()}, nil
+}
+
+func (tag *Tag) addTextFrameIfNotBlank(id string, encoding Encoding, text string) {
+	if text != "" {
+		tag.AddTextFrame(id, encoding, text)
+	}
+}
+
+func (tag *Tag) hasAnyFrame(ids ...string) bool {
+	return len(tag.existingIDs(ids...)) > 0
+}
+
+// existingIDs returns IDs from ids, which frames are in tag.
+func (tag *Tag) existingIDs(ids ...string) []string {
+	var existing []string
+	for _, id := range ids {
+		if tag.GetLastFrame(id) != nil {
+			existing = append(existing, id)
+		}
+	}
+	return existing
+}
+
+func (tag *Tag) deleteFrames(ids ...string) {
+	for _, id := range ids {
+		tag.DeleteFrames(id)
+	}
+}
+
+// deleteExistingFrames deletes frames with ids and returns IDs
+// of frames that were in tag.
+func (tag *Tag) deleteExistingFrames(ids []string) []string {
+	existing := tag.existingIDs(ids...)
+	tag.deleteFrames(existing...)
+	return existing
+}
@@ -0,0 +1,173 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestConvertV23ToV24(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetVersion(3)
+	tag.AddTextFrame("TYER", EncodingISO, "2016")
+	tag.AddTextFrame("TDAT", EncodingISO, "1705")
+	tag.AddTextFrame("TIME", EncodingISO, "1337")
+	tag.AddTextFrame("TORY", EncodingISO, "1999")
+	tag.AddTextFrame("TSIZ", EncodingISO, "123")
+	tag.AddTextFrame("TCON", EncodingISO, "(17)")
+	ipls, err := writeInvolvedPeopleList(EncodingUTF16, []string{"producer", "Foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag.AddFrame("IPLS", ipls)
+
+	unconverted := tag.ConvertVersion(4)
+	if !reflect.DeepEqual(unconverted, []string{"TSIZ"}) {
+		t.Errorf("Expected unconverted frames %v, got %v", []string{"TSIZ"}, unconverted)
+	}
+	if tag.Version() != 4 {
+		t.Fatalf("Expected version 4, got %v", tag.Version())
+	}
+
+	for _, id := range []string{"TYER", "TDAT", "TIME", "TORY", "TSIZ", "IPLS"} {
+		if tag.GetLastFrame(id) != nil {
+			t.Errorf("Expected no %v frame in ID3v2.4 tag", id)
+		}
+	}
+	if got := tag.GetTextFrame("TDRC").Text; got != "2016-05-17T13:37" {
+		t.Errorf("Expected TDRC %q, got %q", "2016-05-17T13:37", got)
+	}
+	if got := tag.GetTextFrame("TDOR").Text; got != "1999" {
+		t.Errorf("Expected TDOR %q, got %q", "1999", got)
+	}
+	if got := tag.Genre(); got != "Rock" {
+		t.Errorf("Expected TCON %q, got %q", "Rock", got)
+	}
+	if got := tag.GetTextFrame("TIPL").Values(); !reflect.DeepEqual(got, []string{"producer", "Foo"}) {
+		t.Errorf("Expected TIPL %q, got %q", []string{"producer", "Foo"}, got)
+	}
+}
+
+func TestConvertV24ToV23(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.SetArtists("Foo", "Bar")
+	tag.AddTextFrame("TDRC", EncodingUTF8, "2016-05-17")
+	tag.AddTextFrame("TMOO", EncodingUTF8, "Calm")
+	tag.AddTextFrameValues("TMCL", EncodingUTF8, "piano", "Baz")
+	tag.AddCommentFrame(engComm)
+
+	unconverted := tag.ConvertVersion(3)
+	if !reflect.DeepEqual(unconverted, []string{"TMOO"}) {
+		t.Errorf("Expected unconverted frames %v, got %v", []string{"TMOO"}, unconverted)
+	}
+
+	if got := tag.Artist(); got != "Foo/Bar" {
+		t.Errorf("Expected artist %q, got %q", "Foo/Bar", got)
+	}
+	if got := tag.GetTextFrame("TIT2").Encoding; !got.Equals(EncodingUTF16) {
+		t.Errorf("Expected title encoding %v, got %v", EncodingUTF16, got)
+	}
+	if got := tag.GetLastFrame("COMM").(CommentFrame).Encoding; !got.Equals(EncodingUTF16) {
+		t.Errorf("Expected comment encoding %v, got %v", EncodingUTF16, got)
+	}
+	if tag.GetTextFrame("TYER").Text != "2016" || tag.GetTextFrame("TDAT").Text != "1705" || tag.GetLastFrame("TIME") != nil {
+		t.Errorf("Expected TYER %q and TDAT %q without TIME", "2016", "1705")
+	}
+	if tag.GetLastFrame("TDRC") != nil || tag.GetLastFrame("TMCL") != nil {
+		t.Error("Expected no ID3v2.4 frames in ID3v2.3 tag")
+	}
+
+	// Write and parse the tag back to check, that it's valid ID3v2.3 tag.
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseReader(buf, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version() != 3 || parsed.Title() != "Title" {
+		t.Errorf("Expected ID3v2.3 tag with title %q, got ID3v2.%v tag with title %q", "Title", parsed.Version(), parsed.Title())
+	}
+	tipl, err := parseInvolvedPeopleList(parsed.GetLastFrame("IPLS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tipl.Values(); !reflect.DeepEqual(got, []string{"piano", "Baz"}) {
+		t.Errorf("Expected IPLS %q, got %q", []string{"piano", "Baz"}, got)
+	}
+}
+
+func TestConvertV24ToV23InvolvedPeopleFailure(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	// Text can't be encoded in ISO-8859-1, so IPLS can't be written.
+	tag.AddTextFrameValues("TIPL", EncodingISO, "composer", "日本")
+	tag.AddTextFrameValues("TMCL", EncodingISO, "piano", "Baz")
+
+	unconverted := tag.ConvertVersion(3)
+	if !reflect.DeepEqual(unconverted, []string{"TIPL", "TMCL"}) {
+		t.Errorf("Expected unconverted frames %v, got %v", []string{"TIPL", "TMCL"}, unconverted)
+	}
+	if tag.GetLastFrame("IPLS") != nil {
+		t.Error("Expected no IPLS frame")
+	}
+}
+
+func TestSetVersionKeepsFrames(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.AddTextFrame("TSOP", EncodingUTF8, "Artist, The")
+	tag.AddTextFrame("TSOA", EncodingUTF8, "Album, The")
+	tag.AddTextFrame("TMOO", EncodingUTF8, "Calm")
+
+	tag.SetVersion(3)
+	if tag.Version() != 3 {
+		t.Fatalf("Expected version 3, got %v", tag.Version())
+	}
+	if tag.Count() != 3 {
+		t.Errorf("Expected 3 frames after SetVersion, got %v", tag.Count())
+	}
+	if got := tag.GetTextFrame("TSOP").Text; got != "Artist, The" {
+		t.Errorf("Expected TSOP %q, got %q", "Artist, The", got)
+	}
+}
+
+func TestConvertVersionKeepsCommonFrames(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.AddTextFrame("TSOP", EncodingISO, "Artist, The")
+	tag.AddTextFrame("TSOA", EncodingISO, "Album, The")
+	tag.AddTextFrame("TSOT", EncodingISO, "Title, The")
+	tag.AddTextFrame("TSST", EncodingISO, "Set subtitle")
+
+	if unconverted := tag.ConvertVersion(3); len(unconverted) != 0 {
+		t.Errorf("Expected no unconverted frames, got %v", unconverted)
+	}
+	if tag.Count() != 4 {
+		t.Errorf("Expected 4 frames in ID3v2.3 tag, got %v", tag.Count())
+	}
+
+	tag.AddFrame("RVAD", UnknownFrame{Body: []byte{0x03, 0x10, 0, 0, 0, 0}})
+	tag.AddFrame("EQUA", UnknownFrame{Body: []byte{0x10}})
+	if unconverted := tag.ConvertVersion(4); len(unconverted) != 0 {
+		t.Errorf("Expected no unconverted frames, got %v", unconverted)
+	}
+	for _, id := range []string{"RVAD", "EQUA", "TSOP", "TSOA", "TSOT", "TSST"} {
+		if tag.GetLastFrame(id) == nil {
+			t.Errorf("Expected %v frame to be kept", id)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func writeTagWithCRC(t *testing.T, version byte) []byte {
+	tag := NewEmptyTag()
+	tag.SetVersion(version)
+	tag.SetCRC(true)
+	tag.SetTitle("Title")
+	tag.SetArtist("Artist")
+	tag.AddCommentFrame(engComm)
+
+	buf := new(bytes.Buffer)
+	n, err := tag.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(n) != tag.Size() {
+		t.Fatalf("Expected written %v bytes, got %v", tag.Size(), n)
+	}
+	return buf.Bytes()
+}
+
+func TestCRC(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []byte{3, 4} {
+		data := writeTagWithCRC(t, version)
+
+		// Check both readers with and without io.Seeker.
+		for _, rd := range []io.Reader{bytes.NewReader(data), bytes.NewBuffer(data)} {
+			tag, err := ParseReader(rd, parseOpts)
+			if err != nil {
+				t.Fatalf("ID3v2.%v: %v", version, err)
+			}
+			if tag.Title() != "Title" || tag.Artist() != "Artist" {
+				t.Errorf("ID3v2.%v: expected title %q and artist %q, got %q and %q", version, "Title", "Artist", tag.Title(), tag.Artist())
+			}
+			if cf, ok := tag.GetLastFrame("COMM").(CommentFrame); !ok || cf.Text != engComm.Text {
+				t.Errorf("ID3v2.%v: expected comment %q, got %v", version, engComm.Text, tag.GetLastFrame("COMM"))
+			}
+
+			// CRC-32 is written again.
+			buf := new(bytes.Buffer)
+			if _, err := tag.WriteTo(buf); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes()[:tagHeaderSize+id3SizeLen], data[:tagHeaderSize+id3SizeLen]) {
+				t.Errorf("ID3v2.%v: expected tag header and extended header size to be written again", version)
+			}
+		}
+	}
+}
+
+func TestCRCV23(t *testing.T) {
+	t.Parallel()
+
+	data := writeTagWithCRC(t, 3)
+	if data[5]&flagExtendedHeader == 0 {
+		t.Fatal("Expected extended header flag")
+	}
+	crc := binary.BigEndian.Uint32(data[20:24])
+	if expected := crc32.ChecksumIEEE(data[24:]); crc != expected {
+		t.Errorf("Expected CRC-32 %x, got %x", expected, crc)
+	}
+}
+
+func TestCRCMismatch(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []byte{3, 4} {
+		data := writeTagWithCRC(t, version)
+		data[len(data)-1]++ // Change the last byte of comment.
+
+		tag, err := ParseReader(bytes.NewReader(data), Options{Parse: true, Lenient: true})
+		if err != nil {
+			t.Errorf("ID3v2.%v: unexpected error in lenient mode: %v", version, err)
+			continue
+		}
+		if tag.Title() != "Title" {
+			t.Errorf("ID3v2.%v: expected parsed frames by CRC-32 mismatch", version)
+		}
+		expected := []Warning{{Offset: tagHeaderSize, Kind: WarningCRCMismatch}}
+		if !reflect.DeepEqual(tag.Warnings(), expected) {
+			t.Errorf("ID3v2.%v: expected warnings %v, got %v", version, expected, tag.Warnings())
+		}
+
+		tag, err = ParseReader(bytes.NewReader(data), Options{Parse: true, Strict: true})
+		if !errors.Is(err, ErrCRCMismatch) {
+			t.Errorf("ID3v2.%v: expected %v in strict mode, got %v", version, ErrCRCMismatch, err)
+		}
+		if tag.Title() != "Title" {
+			t.Errorf("ID3v2.%v: expected parsed frames by CRC-32 mismatch in strict mode", version)
+		}
+	}
+}
@@ -90,6 +90,17 @@ func encodedSize(src string, enc Encoding) int {
 
 }
 
+// encodedValuesSize counts length of UTF-8 values if they're encoded to enc
+// and separated by termination bytes of enc.
+func encodedValuesSize(values []string, enc Encoding) int {
+	bw := getBufWriter(ioutil.Discard)
+	defer putBufWriter(bw)
+
+	encodeWriteTextValues(bw, values, enc)
+
+	return bw.Written()
+}
+
 // decodeText decodes src from "from" encoding to UTF-8.
 func decodeText(src []byte, from Encoding) string {
 	src = bytes.TrimSuffix(src, from.TerminationBytes) // See https://github.com/bogem/id3v2/issues/41
@@ -143,6 +154,57 @@ func encodeWriteText(bw *bufWriter, src string, to Encoding) error {
 	return nil
 }
 
+// encodeWriteTextValues encodes values from UTF-8 to "to" encoding and writes
+// them to bw separated by termination bytes of "to".
+// Unlike encodeWriteText, it writes every value exactly as it's encoded,
+// so the values stay aligned to the length of termination bytes.
+func encodeWriteTextValues(bw *bufWriter, values []string, to Encoding) error {
+	for i, value := range values {
+		if i > 0 {
+			bw.Write(to.TerminationBytes)
+		}
+
+		if to.Equals(EncodingUTF8) {
+			bw.WriteString(value)
+			continue
+		}
+
+		encoded, err := resolveXEncoding(nil, to).NewEncoder().String(value)
+		if err != nil {
+			return err
+		}
+		bw.WriteString(encoded)
+	}
+
+	return nil
+}
+
+// splitEncodedValues splits src by termination bytes of enc. Termination
+// bytes are only searched at positions aligned to their length, so null bytes
+// of UTF-16 code units (e.g. "\x00\x41") are not taken as separators.
+// Termination bytes (and the odd null byte written by encodeWriteText)
+// at the end of src are omitted.
+func splitEncodedValues(src []byte, enc Encoding) [][]byte {
+	delims := enc.TerminationBytes
+	width := len(delims)
+
+	var values [][]byte
+	start := 0
+	for i := 0; i+width <= len(src); i += width {
+		if bytes.Equal(src[i:i+width], delims) {
+			values = append(values, src[start:i])
+			start = i + width
+		}
+	}
+
+	rest := src[start:]
+	if len(values) == 0 || len(bytes.Trim(rest, "\x00")) > 0 {
+		values = append(values, rest)
+	}
+
+	return values
+}
+
 func resolveXEncoding(src []byte, encoding Encoding) encoding.Encoding {
 	switch encoding.Key {
 	case 0:
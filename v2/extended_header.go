@@ -0,0 +1,255 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// Flags of tag header.
+const (
+	flagUnsynchronisation = 1 << 7
+	flagExtendedHeader    = 1 << 6
+	flagFooter            = 1 << 4
+)
+
+// Flag of ID3v2.3 extended header.
+const extFlagV23CRC = 1 << 15
+
+// Flags of ID3v2.4 extended header.
+const (
+	extFlagUpdate       = 1 << 6
+	extFlagCRC          = 1 << 5
+	extFlagRestrictions = 1 << 4
+)
+
+var ErrInvalidExtendedHeader = errors.New("invalid extended header")
+
+// ErrCRCMismatch is returned by parsing in strict mode, if CRC-32
+// in extended header doesn't match CRC-32 of tag data.
+var ErrCRCMismatch = errors.New("CRC-32 of tag data doesn't match the one in extended header")
+
+// extendedHeader is the parsed extended header of tag.
+type extendedHeader struct {
+	// size is the size of the whole extended header in bytes.
+	size int64
+
+	restrictions *Restrictions
+
+	// crc is CRC-32 of tag data, if it's present.
+	crc *uint32
+
+	// paddingSize is the size of padding in ID3v2.3 tag.
+	paddingSize int64
+}
+
+// parseExtendedHeader parses extended header of tag with version in rd.
+// framesSize is the size of tag without tag header.
+func parseExtendedHeader(rd io.Reader, version byte, framesSize int64) (extendedHeader, error) {
+	var eh extendedHeader
+
+	sizeBytes := make([]byte, id3SizeLen)
+	if _, err := io.ReadFull(rd, sizeBytes); err != nil {
+		return eh, err
+	}
+
+	// In ID3v2.3 size is not synchsafe and doesn't include itself.
+	size, err := parseSize(sizeBytes, version == 4)
+	if err != nil {
+		return eh, err
+	}
+	if version == 3 {
+		size += id3SizeLen
+	}
+	if size < id3SizeLen+2 || size > framesSize {
+		return eh, ErrInvalidExtendedHeader
+	}
+	eh.size = size
+
+	data := make([]byte, size-id3SizeLen)
+	if _, err := io.ReadFull(rd, data); err != nil {
+		return eh, err
+	}
+
+	if version == 4 {
+		return eh, eh.parseV24Flags(data)
+	}
+	return eh, eh.parseV23Flags(data, framesSize-size)
+}
+
+// parseV23Flags parses flags of ID3v2.3 extended header and their data.
+// framesSize is the size of frames and padding.
+func (eh *extendedHeader) parseV23Flags(data []byte, framesSize int64) error {
+	if len(data) < 6 {
+		return ErrInvalidExtendedHeader
+	}
+	flags := binary.BigEndian.Uint16(data[0:2])
+
+	eh.paddingSize = int64(binary.BigEndian.Uint32(data[2:6]))
+	if eh.paddingSize > framesSize {
+		return ErrInvalidExtendedHeader
+	}
+
+	if flags&extFlagV23CRC != 0 {
+		if len(data) < 10 {
+			return ErrInvalidExtendedHeader
+		}
+		crc := binary.BigEndian.Uint32(data[6:10])
+		eh.crc = &crc
+	}
+
+	return nil
+}
+
+// parseV24Flags parses flags of ID3v2.4 extended header and their data.
+func (eh *extendedHeader) parseV24Flags(data []byte) error {
+	// data[0] is the number of flag bytes, which is always 1.
+	flags := data[1]
+	data = data[2:]
+
+	for _, flag := range []byte{extFlagUpdate, extFlagCRC, extFlagRestrictions} {
+		if flags&flag == 0 {
+			continue
+		}
+		if len(data) == 0 || len(data) < 1+int(data[0]) {
+			return ErrInvalidExtendedHeader
+		}
+		flagData := data[1 : 1+data[0]]
+		data = data[1+data[0]:]
+
+		switch flag {
+		case extFlagCRC:
+			if len(flagData) != 5 {
+				return ErrInvalidExtendedHeader
+			}
+			// CRC-32 is written as 35-bit synchsafe integer.
+			var crc uint32
+			for _, b := range flagData {
+				crc = crc<<7 | uint32(b&0x7f)
+			}
+			eh.crc = &crc
+		case extFlagRestrictions:
+			if len(flagData) != 1 {
+				return ErrInvalidExtendedHeader
+			}
+			r := parseRestrictions(flagData[0])
+			eh.restrictions = &r
+		}
+	}
+
+	return nil
+}
+
+// crcDataSize returns the size of tag data, which CRC-32 is computed of.
+// framesSize is the size of frames and padding.
+func (eh extendedHeader) crcDataSize(framesSize int64) int64 {
+	// In ID3v2.3 CRC-32 is computed only of frames without padding.
+	return framesSize - eh.paddingSize
+}
+
+// verifyCRC computes CRC-32 of size bytes of tag data in rd and compares it
+// with expected. If rd implements io.Seeker, it seeks back to the beginning
+// of data, otherwise the data is buffered. It returns the reader,
+// which should be used to read the data.
+func verifyCRC(rd io.Reader, size int64, expected uint32) (io.Reader, bool, error) {
+	hash := crc32.NewIEEE()
+
+	if seeker, ok := rd.(io.Seeker); ok {
+		pos, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return rd, false, err
+		}
+		if _, err := io.CopyN(hash, rd, size); err != nil && err != io.EOF {
+			return rd, false, err
+		}
+		if _, err := seeker.Seek(pos, io.SeekStart); err != nil {
+			return rd, false, err
+		}
+		return rd, hash.Sum32() == expected, nil
+	}
+
+	data := new(bytes.Buffer)
+	if _, err := io.CopyN(io.MultiWriter(data, hash), rd, size); err != nil && err != io.EOF {
+		return rd, false, err
+	}
+	return io.MultiReader(data, rd), hash.Sum32() == expected, nil
+}
+
+// SetCRC defines, if WriteTo should compute CRC-32 of tag data
+// and write it in extended header. It's enabled for parsed tags,
+// which have CRC-32.
+func (tag *Tag) SetCRC(enabled bool) {
+	tag.crc = enabled
+}
+
+// extendedHeaderSize returns the size of extended header,
+// how it will be written by WriteTo.
+func (tag *Tag) extendedHeaderSize() int {
+	if tag.version == 3 {
+		if !tag.crc {
+			return 0
+		}
+		// Size, flags, size of padding and CRC-32.
+		return id3SizeLen + 2 + 4 + 4
+	}
+
+	restrictions := tag.activeRestrictions() != nil
+	if !tag.crc && !restrictions {
+		return 0
+	}
+	size := id3SizeLen + 1 + 1 // Size, number of flag bytes and flags
+	if tag.crc {
+		size += 1 + 5 // Length and data of CRC-32
+	}
+	if restrictions {
+		size += 1 + 1 // Length and data of restrictions
+	}
+	return size
+}
+
+// writeExtendedHeader writes extended header of tag with crc of tag data,
+// if it's needed.
+func (tag *Tag) writeExtendedHeader(bw *bufWriter, crc uint32) {
+	size := tag.extendedHeaderSize()
+	if size == 0 {
+		return
+	}
+
+	if tag.version == 3 {
+		bw.WriteBytesSize(uint(size-id3SizeLen), false)
+		bw.Write([]byte{extFlagV23CRC >> 8, 0})
+		bw.Write([]byte{0, 0, 0, 0}) // Size of padding
+		bw.Write([]byte{byte(crc >> 24), byte(crc >> 16), byte(crc >> 8), byte(crc)})
+		return
+	}
+
+	r := tag.activeRestrictions()
+	var flags byte
+	if tag.crc {
+		flags |= extFlagCRC
+	}
+	if r != nil {
+		flags |= extFlagRestrictions
+	}
+
+	bw.WriteBytesSize(uint(size), true)
+	bw.WriteByte(1) // Number of flag bytes
+	bw.WriteByte(flags)
+	if tag.crc {
+		// CRC-32 is written as 35-bit synchsafe integer.
+		bw.WriteByte(5)
+		for shift := 28; shift >= 0; shift -= 7 {
+			bw.WriteByte(byte(crc>>uint(shift)) & 0x7f)
+		}
+	}
+	if r != nil {
+		bw.WriteByte(1)
+		bw.WriteByte(r.byte())
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileTimes are access and modification times of file.
+type fileTimes struct {
+	atime time.Time
+	mtime time.Time
+}
+
+// fileTimes returns times of file with stat, which should be preserved
+// by saving, or nil, if they shouldn't be preserved.
+func (tag *Tag) fileTimes(stat os.FileInfo) *fileTimes {
+	if !tag.preserveTimes {
+		return nil
+	}
+	return &fileTimes{atime: accessTime(stat), mtime: stat.ModTime()}
+}
+
+// writeTempFile creates a unique temp file in fs in the directory of file
+// with name, writes it by write, sets mode and times (if not nil)
+// and syncs it. It returns the name of temp file.
+// If an error occurs, the temp file is removed.
+func writeTempFile(fs FS, name string, mode os.FileMode, times *fileTimes, write func(w io.Writer) error) (_ string, err error) {
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+
+	file, err := fs.CreateTemp(dir, "."+base+".*.id3v2")
+	if err != nil {
+		return "", err
+	}
+	tempName := file.Name()
+
+	// Make sure we clean up the temp file on all error paths.
+	defer func() {
+		if err != nil {
+			file.Close()
+			fs.Remove(tempName)
+		}
+	}()
+
+	if chmoder, ok := file.(interface{ Chmod(os.FileMode) error }); ok {
+		if err = chmoder.Chmod(mode); err != nil {
+			return "", err
+		}
+	}
+	if err = write(file); err != nil {
+		return "", err
+	}
+	if err = file.Sync(); err != nil {
+		return "", err
+	}
+	if err = file.Close(); err != nil {
+		return "", err
+	}
+	if chtimer, ok := fs.(interface {
+		Chtimes(name string, atime, mtime time.Time) error
+	}); ok && times != nil {
+		if err = chtimer.Chtimes(tempName, times.atime, times.mtime); err != nil {
+			return "", err
+		}
+	}
+
+	return tempName, nil
+}
+
+// replaceFile atomically replaces file with name by temp file in fs.
+// If an error occurs, the temp file is removed.
+func replaceFile(fs FS, tempName, name string) error {
+	if err := fs.Rename(tempName, name); err != nil {
+		fs.Remove(tempName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNoFrameToRead is returned by FrameReader.Frame, if Next was not called
+// before, returned an error or the frame was already read.
+var ErrNoFrameToRead = errors.New("there is no frame to read")
+
+// FrameReader reads frames of ID3v2 tag one by one without building a Tag.
+// It uses constant memory regardless of tag size (besides the memory
+// of parsed frames), so it's suitable for processing of many files.
+type FrameReader struct {
+	rd         io.Reader
+	version    byte
+	framesSize int64
+	opts       Options
+
+	// offset and index are the location of the next frame in tag.
+	offset int64
+	index  int
+
+	parseableIDs map[string]bool
+	buf          []byte
+	br           *bufReader
+	bodyRd       *io.LimitedReader
+
+	// cur is the location of frame, which header was read by Next.
+	// Its body can be parsed by Frame, if hasCur is true.
+	cur    frameLocation
+	hasCur bool
+
+	err error
+}
+
+// frameLocation is the location of frame in tag.
+type frameLocation struct {
+	id     string
+	offset int64
+	index  int
+}
+
+// NewFrameReader parses tag header in rd and returns FrameReader, which
+// reads frames of this tag. Options.ParseFrames and Options.FrameParsers
+// are considered, Options.Parse is ignored.
+// If there is no tag in rd, the returned FrameReader has no frames.
+func NewFrameReader(rd io.Reader, opts Options) (*FrameReader, error) {
+	if rd == nil {
+		return nil, errors.New("rd is nil")
+	}
+
+	fr := &FrameReader{rd: rd, version: 4, opts: opts}
+
+	header, err := parseHeader(rd)
+	if err == errNoTag || err == io.EOF {
+		fr.err = io.EOF
+		return fr, nil
+	}
+	if err != nil {
+		return nil, &ParseError{FrameIndex: -1, Err: err}
+	}
+	if header.Version < 3 {
+		return nil, &ParseError{FrameIndex: -1, Err: ErrUnsupportedVersion}
+	}
+
+	fr.version = header.Version
+	fr.framesSize = header.FramesSize
+	fr.offset = tagHeaderSize
+	if header.Flags&flagExtendedHeader != 0 {
+		eh, err := parseExtendedHeader(rd, header.Version, fr.framesSize)
+		if err != nil {
+			return nil, &ParseError{Offset: tagHeaderSize, FrameIndex: -1, Err: err}
+		}
+		fr.framesSize -= eh.size
+		fr.offset += eh.size
+	}
+	if len(opts.ParseFrames) > 0 {
+		fr.parseableIDs = makeIDsFromDescriptions(opts.ParseFrames, fr.version)
+	}
+	fr.buf = make([]byte, 4*1024)
+	fr.br = newBufReader(nil)
+	fr.bodyRd = new(io.LimitedReader)
+
+	return fr, nil
+}
+
+// Version returns ID3v2 version of tag.
+func (fr *FrameReader) Version() byte {
+	return fr.version
+}
+
+// Next reads the header of the next frame and returns its ID and flags.
+// The body of frame is parsed only by Frame, otherwise it's skipped
+// by the next call of Next, so the caller can decide by ID and flags,
+// which frames to parse.
+// At the end of tag Next returns io.EOF, other errors are *ParseError.
+func (fr *FrameReader) Next() (id string, flags uint16, err error) {
+	fr.hasCur = false
+	if fr.err != nil {
+		return "", 0, fr.err
+	}
+
+	offset := fr.offset
+	header, err := fr.next()
+	if err != nil && err != io.EOF {
+		err = &ParseError{Offset: offset, FrameID: header.ID, FrameIndex: fr.index, Err: err}
+	}
+	if err != nil {
+		fr.err = err
+		return "", 0, err
+	}
+	fr.cur = frameLocation{id: header.ID, offset: offset, index: fr.index}
+	fr.hasCur = true
+	fr.index++
+	return header.ID, header.Flags, nil
+}
+
+func (fr *FrameReader) next() (frameHeader, error) {
+	// Skip the rest of previous frame body, if it's not read completely.
+	if err := fr.skipBody(); err != nil {
+		return frameHeader{}, err
+	}
+
+	if fr.framesSize <= 0 {
+		return frameHeader{}, io.EOF
+	}
+
+	header, err := parseFrameHeader(fr.buf, fr.rd, fr.version == 4)
+	if err == io.EOF || err == io.ErrUnexpectedEOF || err == errBlankFrame || err == ErrInvalidSizeFormat {
+		return frameHeader{}, io.EOF
+	}
+	if err != nil {
+		return frameHeader{}, err
+	}
+
+	fr.offset += frameHeaderSize + header.BodySize
+	fr.framesSize -= frameHeaderSize + header.BodySize
+	if fr.framesSize < 0 {
+		return header, ErrBodyOverflow
+	}
+
+	fr.bodyRd.R = fr.rd
+	fr.bodyRd.N = header.BodySize
+	return header, nil
+}
+
+// Frame parses the body of frame, which header was read by the last call
+// of Next. It can be called only once for every frame.
+// If Options.ParseFrames is provided and doesn't contain the ID of frame,
+// Frame returns nil without parsing. Errors are *ParseError, but they
+// don't stop reading of next frames.
+func (fr *FrameReader) Frame() (Framer, error) {
+	if !fr.hasCur {
+		return nil, ErrNoFrameToRead
+	}
+	fr.hasCur = false
+	cur := fr.cur
+
+	if fr.parseableIDs != nil && !fr.parseableIDs[cur.id] {
+		return nil, nil
+	}
+
+	fr.br.Reset(fr.bodyRd)
+	frame, err := parseFrameBody(cur.id, fr.br, fr.version, fr.opts.FrameParsers)
+	if err != nil && err != io.EOF {
+		return nil, &ParseError{Offset: cur.offset, FrameID: cur.id, FrameIndex: cur.index, Err: err}
+	}
+	return frame, nil
+}
+
+// skipBody skips unread bytes of the current frame body.
+func (fr *FrameReader) skipBody() error {
+	if fr.bodyRd == nil || fr.bodyRd.N <= 0 {
+		return nil
+	}
+	if seeker, ok := fr.rd.(io.Seeker); ok {
+		_, err := seeker.Seek(fr.bodyRd.N, io.SeekCurrent)
+		fr.bodyRd.N = 0
+		return err
+	}
+	return skipReaderBuf(fr.bodyRd, fr.buf)
+}
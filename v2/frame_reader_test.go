@@ -0,0 +1,191 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func writeFrameReaderTag(t *testing.T) []byte {
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.SetArtist("Artist")
+	tag.AddAttachedPicture(frontCover)
+	tag.AddCommentFrame(engComm)
+	tag.AddFrame(unknownFrameID, unknownFrame)
+
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	// Append some music data, which should not be read as frames.
+	buf.Write([]byte{255, 251, 80, 0})
+	return buf.Bytes()
+}
+
+func TestFrameReader(t *testing.T) {
+	t.Parallel()
+
+	// Use bytes.Buffer to test the reader without io.Seeker.
+	fr, err := NewFrameReader(bytes.NewBuffer(writeFrameReaderTag(t)), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fr.Version() != 4 {
+		t.Errorf("Expected version 4, got %v", fr.Version())
+	}
+
+	frames := make(map[string]Framer)
+	for {
+		id, _, err := fr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		frame, err := fr.Frame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames[id] = frame
+	}
+
+	if len(frames) != 5 {
+		t.Errorf("Expected 5 frames, got %v", len(frames))
+	}
+	if tf := frames["TIT2"].(TextFrame); tf.Text != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tf.Text)
+	}
+	if err := comparePictureFrames(frames["APIC"].(PictureFrame), frontCover); err != nil {
+		t.Error(err)
+	}
+	if err := compareCommentFrames(frames["COMM"].(CommentFrame), engComm); err != nil {
+		t.Error(err)
+	}
+
+	if _, _, err := fr.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after the end of tag, got %v", err)
+	}
+}
+
+func TestFrameReaderParseFrames(t *testing.T) {
+	t.Parallel()
+
+	fr, err := NewFrameReader(bytes.NewReader(writeFrameReaderTag(t)), Options{ParseFrames: []string{"Title"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for {
+		id, _, err := fr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+
+		frame, err := fr.Frame()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id == "TIT2" && frame == nil {
+			t.Error("Title frame should be parsed")
+		}
+		if id != "TIT2" && frame != nil {
+			t.Errorf("Body of %v frame should be skipped", id)
+		}
+	}
+
+	if len(ids) != 5 {
+		t.Errorf("Expected 5 frames, got %v", ids)
+	}
+}
+
+func TestFrameReaderFlags(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	bw := newBufWriter(buf)
+	writeTagHeader(bw, 16, 4)
+	bw.Write([]byte{'T', 'I', 'T', '2', 0, 0, 0, 6, 0x40, 0x01, 0, 'T', 'i', 't', 'l', 'e'})
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	fr, err := NewFrameReader(buf, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, flags, err := fr.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "TIT2" || flags != 0x4001 {
+		t.Errorf("Expected TIT2 with flags %#x, got %v with %#x", 0x4001, id, flags)
+	}
+}
+
+func TestFrameReaderNoTag(t *testing.T) {
+	t.Parallel()
+
+	fr, err := NewFrameReader(bytes.NewReader([]byte("no tag here")), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := fr.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestFrameReaderSkipFrames(t *testing.T) {
+	t.Parallel()
+
+	// Use bytes.Buffer to test skipping without io.Seeker.
+	fr, err := NewFrameReader(bytes.NewBuffer(writeFrameReaderTag(t)), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := make(map[string]Framer)
+	for {
+		id, _, err := fr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Skip body of picture without parsing.
+		if id == "APIC" {
+			continue
+		}
+		frame, err := fr.Frame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames[id] = frame
+
+		if _, err := fr.Frame(); err != ErrNoFrameToRead {
+			t.Errorf("Expected %v by reading %v frame twice, got %v", ErrNoFrameToRead, id, err)
+		}
+	}
+
+	if len(frames) != 4 {
+		t.Errorf("Expected 4 frames, got %v", len(frames))
+	}
+	if tf := frames["TIT2"].(TextFrame); tf.Text != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tf.Text)
+	}
+	if err := compareCommentFrames(frames["COMM"].(CommentFrame), engComm); err != nil {
+		t.Error(err)
+	}
+}
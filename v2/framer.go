@@ -7,6 +7,7 @@ package id3v2
 import (
 	"errors"
 	"io"
+	"sync"
 )
 
 var ErrInvalidLanguageLength = errors.New("language code must consist of three letters according to ISO 639-2")
@@ -27,3 +28,38 @@ type Framer interface {
 	// WriteTo writes body slice into w.
 	WriteTo(w io.Writer) (n int64, err error)
 }
+
+// FrameParser parses body of frame in tag with given version and returns
+// the parsed frame. body contains only the frame body without frame header.
+type FrameParser func(body io.Reader, version byte) (Framer, error)
+
+var (
+	registeredParsersMu sync.RWMutex
+	registeredParsers   = make(map[string]FrameParser)
+)
+
+// RegisterFrameParser registers parser for frames with id.
+// It will be used for all tags parsed afterwards instead of built-in
+// parser of such frames, so GetFrames returns frames created by parser.
+// If parser is nil, the registered parser for id is removed.
+// Parsers from Options.FrameParsers take precedence over registered ones.
+//
+// It's safe to call RegisterFrameParser concurrently with parsing.
+func RegisterFrameParser(id string, parser FrameParser) {
+	registeredParsersMu.Lock()
+	defer registeredParsersMu.Unlock()
+
+	if parser == nil {
+		delete(registeredParsers, id)
+		return
+	}
+	registeredParsers[id] = parser
+}
+
+func registeredParser(id string) (FrameParser, bool) {
+	registeredParsersMu.RLock()
+	defer registeredParsersMu.RUnlock()
+
+	parser, ok := registeredParsers[id]
+	return parser, ok
+}
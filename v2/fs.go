@@ -0,0 +1,115 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// File is a file, which tags can be opened from and saved to.
+// *os.File implements it.
+//
+// If File implements Chmod(os.FileMode) error, Save uses it to preserve
+// mode of the original file.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Seeker
+	io.Closer
+
+	// Name returns the name of file, how it was passed to FS.Open.
+	Name() string
+
+	Stat() (os.FileInfo, error)
+
+	// Sync commits the written data to stable storage.
+	Sync() error
+}
+
+// FS is a filesystem used by Open, Save and SaveTo. It allows to use
+// id3v2 with in-memory or remote filesystems. OSFS is used by default.
+//
+// If FS implements Chtimes(name string, atime, mtime time.Time) error,
+// Save uses it to preserve times of the original file
+// (see Options.PreserveTimes).
+type FS interface {
+	// Open opens file with name for reading.
+	Open(name string) (File, error)
+
+	// CreateTemp creates a new temp file in dir and opens it for reading
+	// and writing. Name of file is generated from pattern like
+	// ioutil.TempFile does it.
+	CreateTemp(dir, pattern string) (File, error)
+
+	// Rename renames (moves) oldname to newname. If newname already exists,
+	// Rename replaces it. Save relies on atomicity of replacement.
+	Rename(oldname, newname string) error
+
+	// Remove removes file with name.
+	Remove(name string) error
+
+	// Stat returns os.FileInfo of file with name.
+	Stat(name string) (os.FileInfo, error)
+}
+
+// OSFS is FS implemented by the operating system.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (File, error) {
+	return os.Open(name)
+}
+
+func (OSFS) CreateTemp(dir, pattern string) (File, error) {
+	return ioutil.TempFile(dir, pattern)
+}
+
+// Rename renames oldname to newname and syncs the directory of newname,
+// so the replacement is durable.
+func (OSFS) Rename(oldname, newname string) error {
+	if err := os.Rename(oldname, newname); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(newname))
+}
+
+func (OSFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (OSFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSFS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
+
+// syncDir syncs directory, so the entries in it are durable.
+func syncDir(name string) error {
+	// Directories can't be synced on Windows.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// fsOrDefault returns fs or OSFS, if fs is nil.
+func fsOrDefault(fs FS) FS {
+	if fs == nil {
+		return OSFS{}
+	}
+	return fs
+}
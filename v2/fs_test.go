@@ -0,0 +1,242 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memFS is an in-memory FS.
+type memFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	temps int
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: make(map[string][]byte)}
+}
+
+func (fs *memFS) Open(name string) (File, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	data, ok := fs.files[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return &memFile{fs: fs, name: name, data: append([]byte{}, data...)}, nil
+}
+
+func (fs *memFS) CreateTemp(dir, pattern string) (File, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.temps++
+	name := dir + strings.Replace(pattern, "*", strconv.Itoa(fs.temps), 1)
+	fs.files[name] = nil
+	return &memFile{fs: fs, name: name}, nil
+}
+
+func (fs *memFS) Rename(oldname, newname string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	data, ok := fs.files[oldname]
+	if !ok {
+		return os.ErrNotExist
+	}
+	delete(fs.files, oldname)
+	fs.files[newname] = data
+	return nil
+}
+
+func (fs *memFS) Remove(name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, ok := fs.files[name]; !ok {
+		return os.ErrNotExist
+	}
+	delete(fs.files, name)
+	return nil
+}
+
+func (fs *memFS) Stat(name string) (os.FileInfo, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	data, ok := fs.files[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return memFileInfo{name: name, size: int64(len(data))}, nil
+}
+
+func (fs *memFS) names() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	var names []string
+	for name := range fs.files {
+		names = append(names, name)
+	}
+	return names
+}
+
+// memFile is a file of memFS. Written data is stored in memFS by Close.
+type memFile struct {
+	fs      *memFS
+	name    string
+	data    []byte
+	pos     int64
+	written bool
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.pos >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.pos:])
+	f.pos += int64(n)
+	return n, nil
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.data = append(f.data[:f.pos], p...)
+	f.pos += int64(len(p))
+	f.written = true
+	return len(p), nil
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	f.pos = offset
+	return offset, nil
+}
+
+func (f *memFile) Close() error {
+	if f.written {
+		f.fs.mu.Lock()
+		f.fs.files[f.name] = f.data
+		f.fs.mu.Unlock()
+	}
+	return nil
+}
+
+func (f *memFile) Name() string { return f.name }
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	return memFileInfo{name: f.name, size: int64(len(f.data))}, nil
+}
+
+func (f *memFile) Sync() error { return nil }
+
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode  { return 0644 }
+func (fi memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi memFileInfo) IsDir() bool        { return false }
+func (fi memFileInfo) Sys() interface{}   { return nil }
+
+func TestSaveWithFS(t *testing.T) {
+	t.Parallel()
+
+	fs := newMemFS()
+	fs.files["music/song.mp3"] = makeSourceWithMusic(t)
+
+	tag, err := Open("music/song.mp3", Options{Parse: true, FS: fs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tag.Close()
+
+	tag.SetTitle("New title")
+	if err := tag.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if names := fs.names(); len(names) != 1 || names[0] != "music/song.mp3" {
+		t.Errorf("Expected only %q in FS, got %v", "music/song.mp3", names)
+	}
+	testSavedWithMusic(t, fs.files["music/song.mp3"])
+
+	// Tag must be reopened from FS after saving.
+	tag.SetTitle("Title")
+	if err := tag.SaveTo("music/copy.mp3"); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := ParseReader(strings.NewReader(string(fs.files["music/copy.mp3"])), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", saved.Title())
+	}
+	testSavedWithMusic(t, fs.files["music/song.mp3"])
+
+	// SaveTo to the same file works like Save.
+	tag.SetTitle("New title")
+	if err := tag.SaveTo("music/song.mp3"); err != nil {
+		t.Fatal(err)
+	}
+	testSavedWithMusic(t, fs.files["music/song.mp3"])
+	if len(fs.names()) != 2 {
+		t.Errorf("Expected 2 files in FS, got %v", fs.names())
+	}
+}
+
+func TestSaveWithoutFS(t *testing.T) {
+	t.Parallel()
+
+	fs := newMemFS()
+	fs.files["music/song.mp3"] = makeSourceWithMusic(t)
+	original := string(fs.files["music/song.mp3"])
+
+	file, err := fs.Open("music/song.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := ParseReader(file, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// File is not from OS, so its name must not be resolved in OSFS.
+	tag.SetTitle("New title")
+	if err := tag.Save(); err != ErrNoFS {
+		t.Errorf("Save: expected %v, got %v", ErrNoFS, err)
+	}
+	if err := tag.SaveTo("music/copy.mp3"); err != ErrNoFS {
+		t.Errorf("SaveTo: expected %v, got %v", ErrNoFS, err)
+	}
+	if _, err := os.Stat("music"); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing written in OS, got %v", err)
+	}
+	if len(fs.names()) != 1 || string(fs.files["music/song.mp3"]) != original {
+		t.Errorf("Expected untouched FS, got %v", fs.names())
+	}
+}
+
+func TestOpenWithFSNotExist(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Open("song.mp3", Options{FS: newMemFS()}); err != os.ErrNotExist {
+		t.Errorf("Expected %v, got %v", os.ErrNotExist, err)
+	}
+}
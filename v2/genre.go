@@ -0,0 +1,244 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Special genre references, which can be used in content type frame (TCON)
+// besides the ID3v1 genres.
+const (
+	GenreRemix = "Remix"
+	GenreCover = "Cover"
+
+	genreRemixRef = "RX"
+	genreCoverRef = "CR"
+)
+
+// ID3v1Genres is the list of ID3v1 genres including Winamp extensions.
+// The index of genre is its numeric reference, e.g. "(17)" in ID3v2.3
+// or "17" in ID3v2.4 is "Rock".
+var ID3v1Genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge",
+	"Hip-Hop", "Jazz", "Metal", "New Age", "Oldies", "Other", "Pop", "R&B",
+	"Rap", "Reggae", "Rock", "Techno", "Industrial", "Alternative", "Ska",
+	"Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient",
+	"Trip-Hop", "Vocal", "Jazz+Funk", "Fusion", "Trance", "Classical",
+	"Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"Alternative Rock", "Bass", "Soul", "Punk", "Space", "Meditative",
+	"Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic", "Darkwave",
+	"Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream",
+	"Southern Rock", "Comedy", "Cult", "Gangsta", "Top 40", "Christian Rap",
+	"Pop/Funk", "Jungle", "Native American", "Cabaret", "New Wave",
+	"Psychedelic", "Rave", "Showtunes", "Trailer", "Lo-Fi", "Tribal",
+	"Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll",
+	"Hard Rock",
+
+	// Winamp extensions.
+	"Folk", "Folk-Rock", "National Folk", "Swing", "Fast Fusion", "Bebop",
+	"Latin", "Revival", "Celtic", "Bluegrass", "Avantgarde", "Gothic Rock",
+	"Progressive Rock", "Psychedelic Rock", "Symphonic Rock", "Slow Rock",
+	"Big Band", "Chorus", "Easy Listening", "Acoustic", "Humour", "Speech",
+	"Chanson", "Opera", "Chamber Music", "Sonata", "Symphony", "Booty Bass",
+	"Primus", "Porn Groove", "Satire", "Slow Jam", "Club", "Tango", "Samba",
+	"Folklore", "Ballad", "Power Ballad", "Rhythmic Soul", "Freestyle", "Duet",
+	"Punk Rock", "Drum Solo", "A Cappella", "Euro-House", "Dance Hall", "Goa",
+	"Drum & Bass", "Club-House", "Hardcore", "Terror", "Indie", "BritPop",
+	"Afro-Punk", "Polsk Punk", "Beat", "Christian Gangsta Rap", "Heavy Metal",
+	"Black Metal", "Crossover", "Contemporary Christian", "Christian Rock",
+	"Merengue", "Salsa", "Thrash Metal", "Anime", "JPop", "Synthpop",
+	"Abstract", "Art Rock", "Baroque", "Bhangra", "Big Beat", "Breakbeat",
+	"Chillout", "Downtempo", "Dub", "EBM", "Eclectic", "Electro",
+	"Electroclash", "Emo", "Experimental", "Garage", "Global", "IDM",
+	"Illbient", "Industro-Goth", "Jam Band", "Krautrock", "Leftfield",
+	"Lounge", "Math Rock", "New Romantic", "Nu-Breakz", "Post-Punk",
+	"Post-Rock", "Psytrance", "Shoegaze", "Space Rock", "Trop Rock",
+	"World Music", "Neoclassical", "Audiobook", "Audio Theatre",
+	"Neue Deutsche Welle", "Podcast", "Indie Rock", "G-Funk", "Dubstep",
+	"Garage Rock", "Psybient",
+}
+
+// ParseGenres parses text of content type frame (TCON) and returns names
+// of all genres in it. It understands the ID3v2.3 syntax with references
+// in parentheses and optional refinement (e.g. "(17)(18)Eurodisco")
+// as well as multiple ID3v2.4 values with numeric references
+// (e.g. "17\x00Eurodisco"). References "RX" and "CR" are resolved to
+// GenreRemix and GenreCover respectively.
+func ParseGenres(text string) []string {
+	return parseGenres(text, 4)
+}
+
+// parseGenres parses text of content type frame in tag with version.
+// In ID3v2.3 refinement is split by "/" like formatGenres joins it
+// and "//" is unescaped to "/" in genre name.
+func parseGenres(text string, version byte) []string {
+	var genres []string
+
+	for _, value := range strings.Split(text, textValuesSeparator) {
+		for _, genre := range parseGenreValue(value, version == 3) {
+			if !containsString(genres, genre) {
+				genres = append(genres, genre)
+			}
+		}
+	}
+
+	return genres
+}
+
+// parseGenreValue parses one value of content type frame.
+// If splitRefinement is true, refinement is split by splitGenreRefinement.
+func parseGenreValue(value string, splitRefinement bool) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	// ID3v2.4 references are written without parentheses.
+	if genre, ok := resolveGenreRef(value); ok {
+		return []string{genre}
+	}
+
+	var genres []string
+	appendRefinement := func(refinement string) {
+		if !splitRefinement {
+			genres = append(genres, refinement)
+			return
+		}
+		for _, genre := range splitGenreRefinement(refinement) {
+			if genre = strings.TrimSpace(genre); genre != "" {
+				genres = append(genres, genre)
+			}
+		}
+	}
+
+	for len(value) > 0 {
+		// "((" escapes the refinement, which begins with "(".
+		if strings.HasPrefix(value, "((") {
+			appendRefinement(value[1:])
+			break
+		}
+
+		end := strings.IndexByte(value, ')')
+		if value[0] != '(' || end < 0 {
+			appendRefinement(value)
+			break
+		}
+
+		genre, ok := resolveGenreRef(value[1:end])
+		if !ok {
+			appendRefinement(value)
+			break
+		}
+		genres = append(genres, genre)
+		value = strings.TrimSpace(value[end+1:])
+	}
+
+	return genres
+}
+
+// splitGenreRefinement splits ID3v2.3 refinement by "/", which separates
+// genres. "//" is an escaped "/" in genre name, e.g. "AC//DC Tribute/Rock"
+// is split to "AC/DC Tribute" and "Rock".
+func splitGenreRefinement(refinement string) []string {
+	var genres []string
+	var genre strings.Builder
+	for i := 0; i < len(refinement); i++ {
+		c := refinement[i]
+		if c == '/' {
+			if i+1 < len(refinement) && refinement[i+1] == '/' {
+				genre.WriteByte('/')
+				i++
+				continue
+			}
+			genres = append(genres, genre.String())
+			genre.Reset()
+			continue
+		}
+		genre.WriteByte(c)
+	}
+	return append(genres, genre.String())
+}
+
+// resolveGenreRef returns the genre name of ref, which may be an index
+// in ID3v1Genres, "RX" or "CR".
+func resolveGenreRef(ref string) (string, bool) {
+	switch ref {
+	case genreRemixRef:
+		return GenreRemix, true
+	case genreCoverRef:
+		return GenreCover, true
+	}
+
+	i, err := strconv.Atoi(ref)
+	if err != nil || i < 0 || i >= len(ID3v1Genres) {
+		return "", false
+	}
+	return ID3v1Genres[i], true
+}
+
+// genreRef returns the reference of genre, if genre is one of ID3v1Genres,
+// GenreRemix or GenreCover. Genre names are compared case-insensitively.
+func genreRef(genre string) (string, bool) {
+	if strings.EqualFold(genre, GenreRemix) {
+		return genreRemixRef, true
+	}
+	if strings.EqualFold(genre, GenreCover) {
+		return genreCoverRef, true
+	}
+
+	for i, name := range ID3v1Genres {
+		if strings.EqualFold(genre, name) {
+			return strconv.Itoa(i), true
+		}
+	}
+	return "", false
+}
+
+// formatGenres formats genres to text of content type frame in accordance
+// with version. In ID3v2.3 known genres are written as references in
+// parentheses followed by the other genres separated by "/" as refinement,
+// e.g. "(17)(18)Eurodisco". "/" in names of these genres is escaped
+// as "//". In ID3v2.4 genres are written as multiple
+// values and only "RX" and "CR" are used as references.
+func formatGenres(genres []string, version byte) string {
+	if version == 4 {
+		values := make([]string, 0, len(genres))
+		for _, genre := range genres {
+			if ref, ok := genreRef(genre); ok && (ref == genreRemixRef || ref == genreCoverRef) {
+				genre = ref
+			}
+			values = append(values, genre)
+		}
+		return strings.Join(values, textValuesSeparator)
+	}
+
+	var refs strings.Builder
+	var refinements []string
+	for _, genre := range genres {
+		if ref, ok := genreRef(genre); ok {
+			refs.WriteString("(" + ref + ")")
+			continue
+		}
+		refinements = append(refinements, strings.Replace(genre, "/", "//", -1))
+	}
+
+	refinement := strings.Join(refinements, "/")
+	if strings.HasPrefix(refinement, "(") {
+		refinement = "(" + refinement
+	}
+
+	return refs.String() + refinement
+}
+
+func containsString(ss []string, s string) bool {
+	for _, ss := range ss {
+		if ss == s {
+			return true
+		}
+	}
+	return false
+}
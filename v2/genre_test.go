@@ -0,0 +1,100 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestID3v1GenresLen(t *testing.T) {
+	if len(ID3v1Genres) != 192 {
+		t.Errorf("Expected 192 ID3v1 genres, got %v", len(ID3v1Genres))
+	}
+	if ID3v1Genres[17] != "Rock" || ID3v1Genres[191] != "Psybient" {
+		t.Errorf("ID3v1 genres are out of order")
+	}
+}
+
+func TestParseGenres(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text     string
+		expected []string
+	}{
+		{"", nil},
+		{"Rock", []string{"Rock"}},
+		{"(17)", []string{"Rock"}},
+		{"(17)Rock", []string{"Rock"}},
+		{"(4)Eurodisco", []string{"Disco", "Eurodisco"}},
+		{"(17)(18)", []string{"Rock", "Techno"}},
+		{"(RX)(CR)", []string{GenreRemix, GenreCover}},
+		{"((Foo) Bar", []string{"(Foo) Bar"}},
+		{"(999)", []string{"(999)"}},
+		{"17\x00Shoegaze\x00RX", []string{"Rock", "Shoegaze", GenreRemix}},
+		{"(9) Metal", []string{"Metal"}},
+	}
+
+	for _, tc := range testCases {
+		got := ParseGenres(tc.text)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("ParseGenres(%q): expected %q, got %q", tc.text, tc.expected, got)
+		}
+	}
+}
+
+func TestSetGenres(t *testing.T) {
+	t.Parallel()
+
+	genres := []string{"Rock", "Remix", "Eurodisco"}
+
+	testCases := []struct {
+		version  byte
+		expected string
+	}{
+		{3, "(17)(RX)Eurodisco"},
+		{4, "Rock\x00RX\x00Eurodisco"},
+	}
+
+	for _, tc := range testCases {
+		tag := NewEmptyTag()
+		tag.SetVersion(tc.version)
+		tag.SetGenres(genres...)
+
+		if tag.Genre() != tc.expected {
+			t.Errorf("Expected genre %q in ID3v2.%v, got %q", tc.expected, tc.version, tag.Genre())
+		}
+		if !reflect.DeepEqual(tag.Genres(), genres) {
+			t.Errorf("Expected genres %q in ID3v2.%v, got %q", genres, tc.version, tag.Genres())
+		}
+	}
+}
+
+func TestSetGenresRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCases := [][]string{
+		{"Shoegaze", "Dream Pop"},
+		{"Rock", "Shoegaze", "Dream Pop"},
+		{"Pop/Funk", "Eurodisco"},
+		{"(Foo)", "Bar"},
+		{"Rock", "Drum/Bass"},
+		{"AC/DC Tribute"},
+		{"Shoegaze", "AC/DC Tribute", "Drum/Bass"},
+	}
+
+	for _, genres := range testCases {
+		for _, version := range []byte{3, 4} {
+			tag := NewEmptyTag()
+			tag.SetVersion(version)
+			tag.SetGenres(genres...)
+
+			if !reflect.DeepEqual(tag.Genres(), genres) {
+				t.Errorf("Expected genres %q in ID3v2.%v, got %q (text %q)", genres, version, tag.Genres(), tag.Genre())
+			}
+		}
+	}
+}
@@ -22,6 +22,7 @@ var ErrSmallHeaderSize = errors.New("size of tag header is less than expected")
 type tagHeader struct {
 	FramesSize int64
 	Version    byte
+	Flags      byte
 }
 
 // parseHeader parses tag header in rd.
@@ -44,6 +45,7 @@ func parseHeader(rd io.Reader) (tagHeader, error) {
 	}
 
 	header.Version = data[3]
+	header.Flags = data[5]
 
 	// Tag header size is always synchsafe.
 	size, err := parseSize(data[6:], true)
@@ -6,8 +6,8 @@
 package id3v2
 
 import (
+	"context"
 	"io"
-	"os"
 )
 
 // Available picture types for picture frame.
@@ -35,21 +35,42 @@ const (
 	PTPublisherStudioLogotype
 )
 
-// Open opens file with name and passes it to OpenFile.
-// If there is no tag in file, it will create new one with version ID3v2.4.
+// Open opens file with name in opts.FS (OSFS by default) and passes it
+// to ParseReader. If there is no tag in file, it will create new one
+// with version ID3v2.4.
 func Open(name string, opts Options) (*Tag, error) {
-	file, err := os.Open(name)
+	return OpenContext(context.Background(), name, opts)
+}
+
+// OpenContext is like Open, but parsing is stopped, if ctx is done.
+// Then ctx.Err() is returned and file is closed.
+func OpenContext(ctx context.Context, name string, opts Options) (*Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	file, err := fsOrDefault(opts.FS).Open(name)
 	if err != nil {
 		return nil, err
 	}
-	return ParseReader(file, opts)
+	tag, err := ParseReaderContext(ctx, file, opts)
+	if err != nil && err == ctx.Err() {
+		file.Close()
+	}
+	return tag, err
 }
 
 // ParseReader parses rd and finds tag in it considering opts.
 // If there is no tag in rd, it will create new one with version ID3v2.4.
 func ParseReader(rd io.Reader, opts Options) (*Tag, error) {
+	return ParseReaderContext(context.Background(), rd, opts)
+}
+
+// ParseReaderContext is like ParseReader, but ctx is checked before
+// parsing of every frame. If ctx is done, parsing is stopped
+// and ctx.Err() is returned.
+func ParseReaderContext(ctx context.Context, rd io.Reader, opts Options) (*Tag, error) {
 	tag := NewEmptyTag()
-	err := tag.parse(rd, opts)
+	err := tag.parse(ctx, rd, opts)
 	return tag, err
 }
 
@@ -0,0 +1,168 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+var ErrLazyNoReaderAt = errors.New("lazy parsing requires reader to implement io.ReaderAt")
+
+// lazyFrame is the position of frame, which body is not parsed yet.
+type lazyFrame struct {
+	header []byte
+	offset int64
+	size   int64
+
+	// index is the index of frame in tag for ParseError.
+	index int
+}
+
+// lazyLoader keeps positions of frames in tag parsed with Options.Lazy
+// and parses their bodies on demand.
+type lazyLoader struct {
+	ra   io.ReaderAt
+	opts Options
+
+	// version is the version of parsed tag, which can differ from
+	// the version of tag after SetVersion.
+	version byte
+
+	frames map[string][]lazyFrame
+}
+
+func newLazyLoader(rd io.Reader, opts Options, version byte) (*lazyLoader, error) {
+	ra, ok := rd.(io.ReaderAt)
+	if !ok {
+		return nil, ErrLazyNoReaderAt
+	}
+	return &lazyLoader{ra: ra, opts: opts, version: version, frames: make(map[string][]lazyFrame)}, nil
+}
+
+// addFrame keeps the position of index-th frame with header at offset
+// in tag and skips its body in rd.
+func (ll *lazyLoader) addFrame(id string, index int, header []byte, offset int64, rd io.Reader, bodyRd *io.LimitedReader, buf []byte) error {
+	ll.frames[id] = append(ll.frames[id], lazyFrame{
+		header: append([]byte{}, header...),
+		offset: offset,
+		size:   bodyRd.N,
+		index:  index,
+	})
+
+	if seeker, ok := rd.(io.Seeker); ok {
+		_, err := seeker.Seek(bodyRd.N, io.SeekCurrent)
+		return err
+	}
+	return skipReaderBuf(bodyRd, buf)
+}
+
+// load reads and parses the body of lf with id. It returns the frame and
+// its original data, if it should be kept, otherwise nil.
+func (ll *lazyLoader) load(id string, lf lazyFrame) (Framer, []byte, error) {
+	raw := make([]byte, len(lf.header)+int(lf.size))
+	copy(raw, lf.header)
+	body := raw[len(lf.header):]
+
+	n, err := ll.ra.ReadAt(body, lf.offset)
+	if n == len(body) {
+		// ReaderAt may return io.EOF, if body ends at the end of input.
+		err = nil
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	br := getBufReader(bytes.NewReader(body))
+	frame, err := parseFrameBody(id, br, ll.version, ll.opts.FrameParsers)
+	putBufReader(br)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+
+	if !ll.opts.KeepRawFrames {
+		raw = nil
+	}
+	return frame, raw, nil
+}
+
+// loadLazyFrames parses bodies of frames with id, which were not parsed
+// yet, and adds them to tag. Frames, which bodies can't be read or parsed,
+// are not added and stay not parsed, so they're not lost, and the first
+// error is returned as *ParseError.
+func (tag *Tag) loadLazyFrames(id string) error {
+	if tag.lazy == nil {
+		return nil
+	}
+	lfs, ok := tag.lazy.frames[id]
+	if !ok {
+		return nil
+	}
+	delete(tag.lazy.frames, id)
+
+	var (
+		failed   []lazyFrame
+		firstErr error
+	)
+	for _, lf := range lfs {
+		frame, raw, err := tag.lazy.load(id, lf)
+		if err != nil {
+			failed = append(failed, lf)
+			if firstErr == nil {
+				firstErr = &ParseError{Offset: lf.offset - frameHeaderSize, FrameID: id, FrameIndex: lf.index, Err: err}
+			}
+			continue
+		}
+		if tag.version != tag.lazy.version {
+			// Original frames are written only in the version they're parsed.
+			raw = nil
+		}
+		tag.addFrame(id, frame, raw)
+	}
+
+	if len(failed) > 0 {
+		tag.lazy.frames[id] = failed
+	}
+	return firstErr
+}
+
+// loadAllLazyFrames parses bodies of all frames, which were not parsed yet.
+// It returns the first error of loadLazyFrames.
+func (tag *Tag) loadAllLazyFrames() error {
+	if tag.lazy == nil {
+		return nil
+	}
+
+	// Frames, which can't be loaded, are added back to tag.lazy.frames,
+	// so collect IDs before loading.
+	ids := make([]string, 0, len(tag.lazy.frames))
+	for id := range tag.lazy.frames {
+		ids = append(ids, id)
+	}
+
+	var firstErr error
+	for _, id := range ids {
+		if err := tag.loadLazyFrames(id); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// deleteLazyFrames deletes positions of frames with id, which were not
+// parsed yet.
+func (tag *Tag) deleteLazyFrames(id string) {
+	if tag.lazy != nil {
+		delete(tag.lazy.frames, id)
+	}
+}
+
+func (tag *Tag) hasLazyFrames() bool {
+	return tag.lazy != nil && len(tag.lazy.frames) > 0
+}
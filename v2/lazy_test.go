@@ -0,0 +1,187 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestParseLazy(t *testing.T) {
+	tag, err := Open(mp3Path, Options{Parse: true, Lazy: true})
+	if tag == nil || err != nil {
+		t.Fatal("Error while opening mp3 file:", err)
+	}
+	defer tag.Close()
+
+	if len(tag.frames) > 0 || len(tag.sequences) > 0 {
+		t.Fatalf("Expected no parsed frames before access, got %v", len(tag.frames)+len(tag.sequences))
+	}
+	if !tag.HasFrames() {
+		t.Fatal("tag.HasFrames() should return true for lazily parsed tag")
+	}
+
+	testPictureFrames(t, tag)
+	if len(tag.sequences) != 1 {
+		t.Errorf("Expected only picture frames to be parsed, got %v parsed sequences", len(tag.sequences))
+	}
+
+	testTextFrames(t, tag)
+	testUSLTFrames(t, tag)
+	testCommentFrames(t, tag)
+	testUnknownFrames(t, tag)
+
+	if tag.Count() != countOfFrames {
+		t.Errorf("Expected frames: %v, got: %v", countOfFrames, tag.Count())
+	}
+	if tag.Size() != tagSize {
+		t.Errorf("Expected tag.Size(): %v, got: %v", tagSize, tag.Size())
+	}
+}
+
+func TestParseLazyDeleteAndAdd(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.AddCommentFrame(engComm)
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := ParseReader(bytes.NewReader(buf.Bytes()), Options{Parse: true, Lazy: true})
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+
+	// Added frame should replace not parsed one.
+	tag.SetTitle("New title")
+	if tag.Title() != "New title" {
+		t.Errorf("Expected title %q, got %q", "New title", tag.Title())
+	}
+
+	// Added comment should be added to not parsed ones.
+	tag.AddCommentFrame(gerComm)
+	if got := len(tag.GetFrames("COMM")); got != 2 {
+		t.Errorf("Expected 2 comment frames, got %v", got)
+	}
+
+	tag.DeleteFrames("COMM")
+	if tag.Count() != 1 {
+		t.Errorf("Expected 1 frame, got %v", tag.Count())
+	}
+}
+
+func TestParseLazyWithoutReaderAt(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseReader(buf, Options{Parse: true, Lazy: true}); !errors.Is(err, ErrLazyNoReaderAt) {
+		t.Errorf("Expected %v, got %v", ErrLazyNoReaderAt, err)
+	}
+}
+
+// eofReaderAt returns io.EOF with all read bytes, if they end
+// at the end of data, as it's allowed by io.ReaderAt.
+type eofReaderAt struct {
+	*bytes.Reader
+}
+
+func (r eofReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := r.Reader.ReadAt(p, off)
+	if err == nil && off+int64(n) == r.Size() {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// failingReaderAt fails on every ReadAt.
+type failingReaderAt struct {
+	*bytes.Reader
+}
+
+func (failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return 0, errReadAt
+}
+
+var errReadAt = errors.New("read at failed")
+
+func TestParseLazyEOFAtEnd(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := ParseReader(eofReaderAt{bytes.NewReader(buf.Bytes())}, Options{Parse: true, Lazy: true})
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tag.Title())
+	}
+}
+
+func TestParseLazyLoadError(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.SetArtist("Artist")
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	errParse := errors.New("parse failed")
+
+	testCases := []struct {
+		name string
+		rd   io.Reader
+		opts Options
+		err  error
+	}{
+		{"read error", failingReaderAt{bytes.NewReader(buf.Bytes())}, Options{Parse: true, Lazy: true}, errReadAt},
+		{"parse error", bytes.NewReader(buf.Bytes()), Options{Parse: true, Lazy: true, FrameParsers: map[string]FrameParser{
+			"TIT2": func(io.Reader, byte) (Framer, error) { return nil, errParse },
+			"TPE1": func(io.Reader, byte) (Framer, error) { return nil, errParse },
+		}}, errParse},
+	}
+
+	for _, tc := range testCases {
+		tag, err := ParseReader(tc.rd, tc.opts)
+		if err != nil {
+			t.Fatalf("%v: error by parsing tag: %v", tc.name, err)
+		}
+		if tag.Title() != "" {
+			t.Errorf("%v: expected blank title, got %q", tc.name, tag.Title())
+		}
+
+		// Frames, which can't be loaded, must not be lost by writing.
+		if _, err := tag.WriteTo(new(bytes.Buffer)); !errors.Is(err, tc.err) {
+			t.Errorf("%v: expected %v, got %v", tc.name, tc.err, err)
+		}
+		if _, err := tag.Snapshot().WriteTo(new(bytes.Buffer)); !errors.Is(err, tc.err) {
+			t.Errorf("%v: expected %v by writing snapshot, got %v", tc.name, tc.err, err)
+		}
+
+		tag.DeleteFrames("TIT2")
+		tag.DeleteFrames("TPE1")
+		if _, err := tag.WriteTo(new(bytes.Buffer)); err != nil {
+			t.Errorf("%v: expected no error after deleting frames, got %v", tc.name, err)
+		}
+	}
+}
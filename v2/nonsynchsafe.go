@@ -0,0 +1,83 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import "io"
+
+// hasNonSynchSafeSize checks if the size in frame header fh of ID3v2.4 tag
+// is written as non-synchsafe integer. synchSafeSize is the size of frame body
+// decoded as synchsafe integer and framesSize is the size of tag area
+// including fh.
+//
+// The size is non-synchsafe, if it has bytes, which are not allowed in
+// synchsafe integer, or if the frame, which is decoded with synchsafe size,
+// isn't followed by another frame, padding or the end of tag, but it is
+// with non-synchsafe size. The latter can be checked only if the reader
+// of tag implements io.ReadSeeker.
+func (tag *Tag) hasNonSynchSafeSize(fh []byte, synchSafeSize, framesSize int64) bool {
+	if !isValidFrameID(string(fh[:4])) {
+		return false
+	}
+
+	size, err := parseSize(fh[4:8], false)
+	if err != nil || size == 0 || size > framesSize-frameHeaderSize {
+		return false
+	}
+	if _, err := parseSize(fh[4:8], true); err == ErrInvalidSizeFormat {
+		return true
+	}
+	if size == synchSafeSize {
+		return false
+	}
+
+	rs, ok := tag.reader.(io.ReadSeeker)
+	if !ok {
+		return false
+	}
+	remaining := framesSize - frameHeaderSize
+	return !isFrameBoundary(rs, synchSafeSize, remaining, true) && isFrameBoundary(rs, size, remaining, false)
+}
+
+// isFrameBoundary checks if there is the beginning of frame, padding or
+// the end of tag area at offset from the current position in rs.
+// The frame must have valid ID and size, which is decoded considering
+// synchSafe and fits in the tag area. remaining is the rest of tag area
+// from the current position. The position in rs isn't changed.
+func isFrameBoundary(rs io.ReadSeeker, offset, remaining int64, synchSafe bool) bool {
+	if offset == remaining {
+		return true
+	}
+	if offset > remaining {
+		return false
+	}
+
+	pos, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false
+	}
+	defer rs.Seek(pos, io.SeekStart)
+
+	if _, err := rs.Seek(offset, io.SeekCurrent); err != nil {
+		return false
+	}
+
+	n := remaining - offset
+	if n > frameHeaderSize {
+		n = frameHeaderSize
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rs, b); err != nil {
+		return false
+	}
+
+	if isBlank(b) {
+		return true
+	}
+	if n < frameHeaderSize {
+		return false
+	}
+	header, err := decodeFrameHeader(b, synchSafe)
+	return err == nil && isValidFrameID(header.ID) && header.BodySize <= remaining-offset-frameHeaderSize
+}
@@ -0,0 +1,90 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeITunesTag writes ID3v2.4 tag with non-synchsafe frame sizes
+// and title of titleLen characters.
+func writeITunesTag(t *testing.T, titleLen int) []byte {
+	title := TextFrame{Encoding: EncodingISO, Text: strings.Repeat("A", titleLen)}
+	artist := TextFrame{Encoding: EncodingISO, Text: "Artist"}
+	framesSize := FrameSize(title) + FrameSize(artist) + 10 // with padding
+
+	buf := new(bytes.Buffer)
+	bw := newBufWriter(buf)
+	writeTagHeader(bw, uint(framesSize), 4)
+	for _, f := range []struct {
+		id string
+		tf TextFrame
+	}{{"TIT2", title}, {"TPE1", artist}} {
+		writeFrameHeader(bw, f.id, uint(f.tf.Size()), false)
+		f.tf.WriteTo(bw)
+	}
+	bw.Write(make([]byte, 10))
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseNonSynchSafeFrameSizes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		titleLen int
+	}{
+		// Size 0xC8 has byte, which is not allowed in synchsafe integer.
+		{"invalid synchsafe size", 199},
+		// Size 0x012C is valid synchsafe integer, but points to the middle of frame.
+		{"valid synchsafe size", 299},
+	}
+
+	for _, tc := range testCases {
+		data := writeITunesTag(t, tc.titleLen)
+
+		tag, err := ParseReader(bytes.NewReader(data), Options{Parse: true, Lenient: true})
+		if err != nil {
+			t.Errorf("%v: %v", tc.name, err)
+			continue
+		}
+		if len(tag.Title()) != tc.titleLen || tag.Artist() != "Artist" {
+			t.Errorf("%v: expected title of length %v and artist %q, got %q and %q", tc.name, tc.titleLen, "Artist", tag.Title(), tag.Artist())
+		}
+		expected := []Warning{{Offset: tagHeaderSize, FrameID: "TIT2", Kind: WarningNonSynchSafeSize}}
+		if !reflect.DeepEqual(tag.Warnings(), expected) {
+			t.Errorf("%v: expected warnings %v, got %v", tc.name, expected, tag.Warnings())
+		}
+	}
+}
+
+func TestParseSynchSafeFrameSizesNotFixed(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle(strings.Repeat("A", 299))
+	tag.SetArtist("Artist")
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseReader(bytes.NewReader(buf.Bytes()), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got %v", parsed.Warnings())
+	}
+	if parsed.Artist() != "Artist" {
+		t.Errorf("Expected artist %q, got %q", "Artist", parsed.Artist())
+	}
+}
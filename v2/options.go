@@ -19,4 +19,53 @@ type Options struct {
 	// if you want to get only some text frames,
 	// id3v2 will not parse huge picture or unknown frames.
 	ParseFrames []string
+
+	// FrameParsers defines parsers for frames with corresponding IDs.
+	// They are used instead of parsers registered by RegisterFrameParser
+	// and built-in ones. It works only if Parse is true.
+	FrameParsers map[string]FrameParser
+
+	// KeepRawFrames defines, if original header and body bytes of every
+	// parsed frame should be kept. Then WriteTo writes frames, which were
+	// not replaced or deleted since parsing, byte-for-byte identical to
+	// the original ones, including frame flags and data that id3v2 can't
+	// parse. Original frames are dropped, if the version of tag is changed.
+	// It works only if Parse is true.
+	KeepRawFrames bool
+
+	// Lazy defines, if frame bodies should be parsed only when they're
+	// accessed for the first time (e.g. by GetFrames). By parsing only
+	// frame headers and positions of frames are read, so it's very useful
+	// if you don't need all frames, e.g. huge pictures.
+	// The reader must implement io.ReaderAt (like *os.File) and stay open
+	// until all needed frames are accessed. The tag must begin at the start
+	// of the reader. Frames, which bodies can't be read or parsed, are not
+	// accessible, but they're not lost: WriteTo and Save return the error
+	// until such frames are deleted. It works only if Parse is true.
+	Lazy bool
+
+	// Strict defines, if parsing should return an error by any violation
+	// of ID3v2 specification in frame headers, padding and CRC-32 of tag,
+	// e.g. invalid frame ID or size, frame going over tag area, junk
+	// in padding, CRC-32 mismatch or truncated tag.
+	// By default parsing returns only ErrBodyOverflow, if frame goes over
+	// tag area, and silently recovers from other violations, mostly
+	// by skipping the rest of tag. It works only if Parse is true.
+	Strict bool
+
+	// Lenient defines, if parsing should recover from all violations,
+	// which Strict turns into errors, including frame going over tag area,
+	// and record them as warnings, which can be retrieved by Tag.Warnings.
+	// It's ignored, if Strict is true. It works only if Parse is true.
+	Lenient bool
+
+	// PreserveTimes defines, if Save and SaveTo should preserve access
+	// and modification times of the original file.
+	PreserveTimes bool
+
+	// FS is the filesystem, which Open opens file from and Save writes
+	// file to. If it's nil, OSFS is used. If tag is parsed by ParseReader
+	// with File, Save uses FS as filesystem of this file. Then FS is
+	// required, if File is not *os.File (see ErrNoFS).
+	FS FS
 }
@@ -0,0 +1,72 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import "io"
+
+// Padding describes padding of parsed tag.
+type Padding struct {
+	// Offset is the offset from the start of tag, where frames end
+	// and padding begins.
+	Offset int64
+
+	// Size is the size of padding in bytes.
+	Size int64
+
+	// HasJunk defines, if there are non-zero bytes in padding.
+	// Then parsing in lenient mode also records WarningJunkInPadding.
+	HasJunk bool
+}
+
+// Padding returns padding of parsed tag. ok is false, if padding is unknown,
+// e.g. if tag is not parsed, parsed only partially because of
+// Options.ParseFrames or parsing stopped because of invalid frame.
+// It's useful to decide if the tag can be updated in place.
+func (tag *Tag) Padding() (p Padding, ok bool) {
+	if tag.padding == nil {
+		return Padding{}, false
+	}
+	return *tag.padding, true
+}
+
+// parsePadding reads padding of tag with readPadding and records
+// WarningJunkInPadding, if there are non-zero bytes in it.
+func (tag *Tag) parsePadding(opts Options, offset, size int64, read, buf []byte) error {
+	padding, err := readPadding(tag.reader, offset, size, read, buf)
+	if err != nil {
+		return err
+	}
+	tag.padding = padding
+	if padding.HasJunk {
+		return tag.warn(opts, offset, "", WarningJunkInPadding)
+	}
+	return nil
+}
+
+// readPadding reads padding of size bytes beginning at offset in tag
+// from rd and checks if there are non-zero bytes in it. read are bytes
+// of padding, which are already read from rd. buf is used for reading
+// and may be overlapped with read.
+func readPadding(rd io.Reader, offset, size int64, read, buf []byte) (*Padding, error) {
+	padding := &Padding{Offset: offset, Size: size, HasJunk: !isBlank(read)}
+
+	rest := getLimitedReader(rd, size-int64(len(read)))
+	defer putLimitedReader(rest)
+
+	for !padding.HasJunk {
+		n, err := rest.Read(buf)
+		if !isBlank(buf[:n]) {
+			padding.HasJunk = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return padding, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPadding(t *testing.T) {
+	t.Parallel()
+
+	junk := make([]byte, 20)
+	junk[15] = 'J'
+
+	testCases := []struct {
+		name    string
+		data    []byte
+		padding Padding
+	}{
+		{"no padding", makeTagWithTitle(t, 16, nil), Padding{Offset: 26}},
+		{"padding", makeTagWithTitle(t, 16+20, make([]byte, 20)), Padding{Offset: 26, Size: 20}},
+		{"small padding", makeTagWithTitle(t, 16+5, make([]byte, 5)), Padding{Offset: 26, Size: 5}},
+		{"junk in padding", makeTagWithTitle(t, 16+20, junk), Padding{Offset: 26, Size: 20, HasJunk: true}},
+	}
+
+	for _, tc := range testCases {
+		// Append some music data, which should not be read as padding.
+		data := append(tc.data, 255, 251, 80, 0)
+
+		tag, err := ParseReader(bytes.NewReader(data), Options{Parse: true, Lenient: true})
+		if err != nil {
+			t.Errorf("%v: %v", tc.name, err)
+			continue
+		}
+		padding, ok := tag.Padding()
+		if !ok {
+			t.Errorf("%v: expected known padding", tc.name)
+		}
+		if padding != tc.padding {
+			t.Errorf("%v: expected padding %+v, got %+v", tc.name, tc.padding, padding)
+		}
+		if warned := len(tag.Warnings()) != 0; warned != tc.padding.HasJunk {
+			t.Errorf("%v: expected warning only by junk in padding, got %v", tc.name, tag.Warnings())
+		}
+	}
+}
+
+func TestPaddingUnknown(t *testing.T) {
+	t.Parallel()
+
+	data := makeTagWithTitle(t, 16+20, make([]byte, 20))
+
+	tag, err := ParseReader(bytes.NewReader(data), Options{Parse: true, ParseFrames: []string{"Title"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tag.Padding(); ok {
+		t.Error("Expected unknown padding, if tag is parsed partially")
+	}
+
+	if _, ok := NewEmptyTag().Padding(); ok {
+		t.Error("Expected unknown padding of empty tag")
+	}
+}
@@ -5,8 +5,10 @@
 package id3v2
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"errors"
-	"fmt"
 	"io"
 )
 
@@ -15,20 +17,29 @@ const frameHeaderSize = 10
 var ErrUnsupportedVersion = errors.New("unsupported version of ID3 tag")
 var errBlankFrame = errors.New("id or size of frame are blank")
 
-// ErrBodyOverflow is returned when a frame has greater size than the remaining tag size
+// ErrBodyOverflow is returned when a frame has greater size than the remaining tag size.
+// If tag is parsed with Options.Lenient, WarningBodyOverflow is recorded instead.
 var ErrBodyOverflow = errors.New("frame went over tag area")
 
 type frameHeader struct {
 	ID       string
 	BodySize int64
+	Flags    uint16
 }
 
 // parse finds ID3v2 tag in rd and parses it to tag considering opts.
 // If rd is smaller than expected, it returns ErrSmallHeaderSize.
-func (tag *Tag) parse(rd io.Reader, opts Options) error {
+// If ctx is done, it returns ctx.Err().
+func (tag *Tag) parse(ctx context.Context, rd io.Reader, opts Options) error {
 	if rd == nil {
 		return errors.New("rd is nil")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tag.preserveTimes = opts.PreserveTimes
+	tag.fs = opts.FS
 
 	header, err := parseHeader(rd)
 	if err == errNoTag || err == io.EOF {
@@ -36,32 +47,89 @@ func (tag *Tag) parse(rd io.Reader, opts Options) error {
 		return nil
 	}
 	if err != nil {
-		return fmt.Errorf("error by parsing tag header: %v", err)
+		return &ParseError{FrameIndex: -1, Err: err}
 	}
 	if header.Version < 3 {
-		return ErrUnsupportedVersion
+		return &ParseError{FrameIndex: -1, Err: ErrUnsupportedVersion}
 	}
 
-	tag.init(rd, tagHeaderSize+header.FramesSize, header.Version)
+	framesEnd := tagHeaderSize + header.FramesSize
+	originalSize := framesEnd
+	if header.Version == 4 && header.Flags&flagFooter != 0 {
+		// Footer is a part of the original tag, not of the music part.
+		originalSize += tagFooterSize
+	}
+	tag.init(rd, originalSize, header.Version)
 	if !opts.Parse {
 		return nil
 	}
-	return tag.parseFrames(opts)
+
+	framesSize := header.FramesSize
+	crcMatches := true
+	if header.Flags&flagExtendedHeader != 0 {
+		eh, err := parseExtendedHeader(rd, header.Version, framesSize)
+		if err != nil {
+			return &ParseError{Offset: tagHeaderSize, FrameIndex: -1, Err: err}
+		}
+		tag.restrictions = eh.restrictions
+		framesSize -= eh.size
+
+		if eh.crc != nil {
+			tag.crc = true
+			tag.reader, crcMatches, err = verifyCRC(rd, eh.crcDataSize(framesSize), *eh.crc)
+			if err != nil {
+				return &ParseError{Offset: tagHeaderSize, FrameIndex: -1, Err: err}
+			}
+		}
+	}
+
+	if opts.Lazy {
+		if tag.lazy, err = newLazyLoader(rd, opts, tag.version); err != nil {
+			return &ParseError{Offset: tagHeaderSize, FrameIndex: -1, Err: err}
+		}
+	}
+	if err := tag.parseFrames(ctx, opts, framesSize, framesEnd); err != nil {
+		return err
+	}
+	if !crcMatches {
+		if err := tag.warn(opts, tagHeaderSize, "", WarningCRCMismatch); err != nil {
+			return &ParseError{Offset: tagHeaderSize, FrameIndex: -1, Err: err}
+		}
+	}
+	return nil
 }
 
 func (tag *Tag) init(rd io.Reader, originalSize int64, version byte) {
 	tag.DeleteAllFrames()
 
 	tag.reader = rd
+	tag.warnings = nil
+	tag.padding = nil
+	tag.restrictions = nil
+	tag.crc = false
+	tag.trailers = nil
 	tag.originalSize = originalSize
 	tag.version = version
 	tag.setDefaultEncodingBasedOnVersion(version)
 }
 
-func (tag *Tag) parseFrames(opts Options) error {
-	framesSize := tag.originalSize - tagHeaderSize
+// parseFrames parses frames in tag area of framesSize bytes
+// after tag header and extended header. framesEnd is the offset of the end
+// of tag area, i.e. of footer, if it's present.
+func (tag *Tag) parseFrames(ctx context.Context, opts Options, framesSize, framesEnd int64) (err error) {
+	// Location of the current frame for ParseError.
+	var (
+		offset int64
+		id     string
+		index  int
+	)
+	defer func() {
+		if err != nil && err != ctx.Err() {
+			err = &ParseError{Offset: offset, FrameID: id, FrameIndex: index, Err: err}
+		}
+	}()
 
-	parseableIDs := tag.makeIDsFromDescriptions(opts.ParseFrames)
+	parseableIDs := makeIDsFromDescriptions(opts.ParseFrames, tag.version)
 	isParseFramesProvided := len(opts.ParseFrames) > 0
 
 	synchSafe := tag.Version() == 4
@@ -72,38 +140,145 @@ func (tag *Tag) parseFrames(opts Options) error {
 	buf := getByteSlice(32 * 1024)
 	defer putByteSlice(buf)
 
-	for framesSize > 0 {
+	for ; framesSize > 0; index++ {
+		offset, id = framesEnd-framesSize, ""
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// There is no space for frame, so it's padding.
+		if framesSize < frameHeaderSize {
+			if err := tag.parsePadding(opts, offset, framesSize, nil, buf); err != nil {
+				return err
+			}
+			break
+		}
+
 		header, err := parseFrameHeader(buf, tag.reader, synchSafe)
-		if err == io.EOF || err == errBlankFrame || err == ErrInvalidSizeFormat {
+		if synchSafe && (err == nil || err == ErrInvalidSizeFormat) &&
+			tag.hasNonSynchSafeSize(buf[:frameHeaderSize], header.BodySize, framesSize) {
+			// Use non-synchsafe sizes for the rest of tag.
+			synchSafe = false
+			if err := tag.warn(opts, offset, validFrameID(buf[:4]), WarningNonSynchSafeSize); err != nil {
+				return err
+			}
+			header, err = decodeFrameHeader(buf[:frameHeaderSize], synchSafe)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if err := tag.warn(opts, offset, "", WarningTruncated); err != nil {
+				return err
+			}
+			break
+		}
+		if err == errBlankFrame || err == ErrInvalidSizeFormat {
+			// Frame header beginning with zero byte is the beginning of padding.
+			if buf[0] == 0 {
+				if err := tag.parsePadding(opts, offset, framesSize, buf[:frameHeaderSize], buf); err != nil {
+					return err
+				}
+				break
+			}
+			kind := WarningInvalidFrameHeader
+			if err == ErrInvalidSizeFormat {
+				kind = WarningInvalidSize
+			}
+			id = validFrameID(buf[:4])
+			if err := tag.warn(opts, offset, id, kind); err != nil {
+				return err
+			}
 			break
 		}
 		if err != nil {
 			return err
 		}
-		id, bodySize := header.ID, header.BodySize
+		bodySize := header.BodySize
+
+		if !isValidFrameID(header.ID) {
+			// Frame with blank ID is junk in padding.
+			if header.ID[0] == 0 {
+				if err := tag.parsePadding(opts, offset, framesSize, buf[:frameHeaderSize], buf); err != nil {
+					return err
+				}
+				break
+			}
+			if err := tag.warn(opts, offset, "", WarningInvalidFrameHeader); err != nil {
+				return err
+			}
+		}
+		id = header.ID
 
 		framesSize -= frameHeaderSize + bodySize
 		if framesSize < 0 {
-			return ErrBodyOverflow
+			if err := tag.warn(opts, offset, id, WarningBodyOverflow); err != nil {
+				return err
+			}
+			break
 		}
 
 		bodyRd := getLimitedReader(tag.reader, bodySize)
 		defer putLimitedReader(bodyRd)
 
+		if !synchSafe && tag.version == 4 {
+			fixRawFrameHeaderSize(buf[:frameHeaderSize], bodySize)
+		}
+
 		if isParseFramesProvided && !parseableIDs[id] {
 			if err := skipReaderBuf(bodyRd, buf); err != nil {
 				return err
 			}
+			if bodyRd.N > 0 {
+				if err := tag.warn(opts, offset, id, WarningTruncated); err != nil {
+					return err
+				}
+				break
+			}
 			continue
 		}
 
-		br.Reset(bodyRd)
-		frame, err := parseFrameBody(id, br, tag.version)
+		if tag.lazy != nil {
+			bodyOffset := offset + frameHeaderSize
+			if err := tag.lazy.addFrame(id, index, buf[:frameHeaderSize], bodyOffset, tag.reader, bodyRd, buf); err != nil {
+				return err
+			}
+			if isParseFramesProvided && !mustFrameBeInSequence(id) {
+				delete(parseableIDs, id)
+				if len(parseableIDs) == 0 {
+					break
+				}
+			}
+			continue
+		}
+
+		var raw []byte
+		if opts.KeepRawFrames {
+			raw, err = readRawFrame(buf[:frameHeaderSize], bodyRd)
+			if err != nil {
+				return err
+			}
+			br.Reset(bytes.NewReader(raw[frameHeaderSize:]))
+		} else {
+			br.Reset(bodyRd)
+		}
+
+		frame, err := parseFrameBody(id, br, tag.version, opts.FrameParsers)
 		if err != nil && err != io.EOF {
 			return err
 		}
 
-		tag.AddFrame(id, frame)
+		tag.addFrame(id, frame, completeRawFrame(raw, bodySize))
+
+		// Skip the rest of body, which the parser didn't read,
+		// to check if it's complete.
+		if err := skipReaderBuf(bodyRd, buf); err != nil {
+			return err
+		}
+		if bodyRd.N > 0 {
+			if err := tag.warn(opts, offset, id, WarningTruncated); err != nil {
+				return err
+			}
+			break
+		}
 
 		if isParseFramesProvided && !mustFrameBeInSequence(id) {
 			delete(parseableIDs, id)
@@ -120,14 +295,19 @@ func (tag *Tag) parseFrames(opts Options) error {
 		}
 	}
 
+	// All frames are parsed and there is no padding.
+	if framesSize == 0 && tag.padding == nil {
+		tag.padding = &Padding{Offset: framesEnd}
+	}
+
 	return nil
 }
 
-func (tag *Tag) makeIDsFromDescriptions(parseFrames []string) map[string]bool {
+func makeIDsFromDescriptions(parseFrames []string, version byte) map[string]bool {
 	ids := make(map[string]bool, len(parseFrames))
 
 	for _, description := range parseFrames {
-		ids[tag.CommonID(description)] = true
+		ids[commonID(description, version)] = true
 	}
 
 	return ids
@@ -141,10 +321,17 @@ func parseFrameHeader(buf []byte, rd io.Reader, synchSafe bool) (frameHeader, er
 	}
 
 	fhBuf := buf[:frameHeaderSize]
-	if _, err := rd.Read(fhBuf); err != nil {
+	if _, err := io.ReadFull(rd, fhBuf); err != nil {
 		return header, err
 	}
 
+	return decodeFrameHeader(fhBuf, synchSafe)
+}
+
+// decodeFrameHeader decodes frame header in fhBuf.
+func decodeFrameHeader(fhBuf []byte, synchSafe bool) (frameHeader, error) {
+	var header frameHeader
+
 	id := string(fhBuf[:4])
 	bodySize, err := parseSize(fhBuf[4:8], synchSafe)
 	if err != nil {
@@ -157,6 +344,7 @@ func parseFrameHeader(buf []byte, rd io.Reader, synchSafe bool) (frameHeader, er
 
 	header.ID = id
 	header.BodySize = bodySize
+	header.Flags = binary.BigEndian.Uint16(fhBuf[8:10])
 	return header, nil
 }
 
@@ -174,7 +362,16 @@ func skipReaderBuf(rd io.Reader, buf []byte) error {
 	return nil
 }
 
-func parseFrameBody(id string, br *bufReader, version byte) (Framer, error) {
+// parseFrameBody parses frame body in br. At first it looks for the parser
+// of frame in customParsers, then in registered and built-in ones.
+func parseFrameBody(id string, br *bufReader, version byte, customParsers map[string]FrameParser) (Framer, error) {
+	if parser, exists := customParsers[id]; exists {
+		return parser(br, version)
+	}
+	if parser, exists := registeredParser(id); exists {
+		return parser(br, version)
+	}
+
 	if id[0] == 'T' && id != "TXXX" {
 		return parseTextFrame(br)
 	}
@@ -0,0 +1,43 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import "fmt"
+
+// ParseError describes where an error occurred by parsing of tag.
+// It can be used with errors.Is and errors.As, e.g.
+// errors.Is(err, ErrBodyOverflow) checks if err is ParseError
+// caused by ErrBodyOverflow.
+type ParseError struct {
+	// Offset is the offset of frame header from the start of tag.
+	// It's 0 if the error occurred in tag header.
+	Offset int64
+
+	// FrameID is the ID of frame. It can be blank, if the error occurred
+	// in tag header or the ID is unknown.
+	FrameID string
+
+	// FrameIndex is the index of frame in tag, counting from 0.
+	// It's -1 if the error occurred in tag header.
+	FrameIndex int
+
+	// Err is the cause of error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.FrameIndex < 0 {
+		return fmt.Sprintf("error by parsing tag header: %v", e.Err)
+	}
+	if e.FrameID == "" {
+		return fmt.Sprintf("error by parsing frame #%d at offset %d: %v", e.FrameIndex, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("error by parsing frame %q (#%d) at offset %d: %v", e.FrameID, e.FrameIndex, e.Offset, e.Err)
+}
+
+// Unwrap returns the cause of error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
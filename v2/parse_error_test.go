@@ -0,0 +1,93 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	data := makeTagWithTitle(t, 16+12, []byte{0x54, 0x50, 0x45, 0x31, 0, 0, 0, 6, 0, 0, 3, 'A'})
+
+	_, err := ParseReader(bytes.NewReader(data), Options{Parse: true, Strict: true})
+	if !errors.Is(err, ErrBodyOverflow) {
+		t.Fatalf("Expected %v, got %v", ErrBodyOverflow, err)
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected *ParseError, got %T", err)
+	}
+	expected := ParseError{Offset: 26, FrameID: "TPE1", FrameIndex: 1, Err: ErrBodyOverflow}
+	if *pe != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *pe)
+	}
+	if got := pe.Error(); got != `error by parsing frame "TPE1" (#1) at offset 26: frame went over tag area` {
+		t.Errorf("Unexpected error message: %q", got)
+	}
+}
+
+func TestParseErrorInTagHeader(t *testing.T) {
+	t.Parallel()
+
+	data := makeTagWithTitle(t, 16, nil)
+	data[6] = 255 // Size byte can't be greater than 127.
+
+	_, err := ParseReader(bytes.NewReader(data), parseOpts)
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected *ParseError, got %v", err)
+	}
+	if pe.FrameIndex != -1 || !errors.Is(err, ErrInvalidSizeFormat) {
+		t.Errorf("Expected error in tag header caused by %v, got %+v", ErrInvalidSizeFormat, *pe)
+	}
+}
+
+func TestParseErrorUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	data := makeTagWithTitle(t, 16, nil)
+	data[3] = 2
+
+	_, err := ParseReader(bytes.NewReader(data), parseOpts)
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected *ParseError, got %v", err)
+	}
+	if pe.FrameIndex != -1 || !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Expected error in tag header caused by %v, got %+v", ErrUnsupportedVersion, *pe)
+	}
+
+	if _, err := NewFrameReader(bytes.NewReader(data), Options{}); !errors.As(err, &pe) || !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Expected *ParseError caused by %v, got %v", ErrUnsupportedVersion, err)
+	}
+}
+
+func TestFrameReaderParseError(t *testing.T) {
+	t.Parallel()
+
+	data := makeTagWithTitle(t, 16+12, []byte{0x54, 0x50, 0x45, 0x31, 0, 0, 0, 6, 0, 0, 3, 'A'})
+
+	fr, err := NewFrameReader(bytes.NewReader(data), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := fr.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = fr.Next()
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected *ParseError, got %v", err)
+	}
+	expected := ParseError{Offset: 26, FrameID: "TPE1", FrameIndex: 1, Err: ErrBodyOverflow}
+	if *pe != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *pe)
+	}
+}
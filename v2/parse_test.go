@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 )
@@ -517,3 +519,117 @@ func TestParseV3UnsafeSize(t *testing.T) {
 		t.Fatalf("Titles are not equal: len(parsedTag.Title()) == %v, len(title) == %v", len(parsedTag.Title()), len(title))
 	}
 }
+
+// vendorFrame is a custom frame used to test registration of frame parsers.
+type vendorFrame struct {
+	Data string
+}
+
+func (vf vendorFrame) Size() int                { return len(vf.Data) }
+func (vf vendorFrame) UniqueIdentifier() string { return vf.Data }
+func (vf vendorFrame) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, vf.Data)
+	return int64(n), err
+}
+
+func parseVendorFrame(body io.Reader, version byte) (Framer, error) {
+	data, err := ioutil.ReadAll(body)
+	return vendorFrame{Data: string(data)}, err
+}
+
+func writeVendorTag(t *testing.T, id string) *bytes.Buffer {
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.AddFrame(id, vendorFrame{Data: "vendor data"})
+
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal("Error by writing tag:", err)
+	}
+	return buf
+}
+
+func testVendorFrame(t *testing.T, tag *Tag, id string) {
+	frames := tag.GetFrames(id)
+	if len(frames) != 1 {
+		t.Fatalf("Expected 1 %v frame, got %v", id, len(frames))
+	}
+	vf, ok := frames[0].(vendorFrame)
+	if !ok {
+		t.Fatalf("Expected vendorFrame, got %T", frames[0])
+	}
+	if vf.Data != "vendor data" {
+		t.Errorf("Expected data %q, got %q", "vendor data", vf.Data)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tag.Title())
+	}
+}
+
+func TestParseOptionsFrameParsers(t *testing.T) {
+	t.Parallel()
+
+	buf := writeVendorTag(t, "XOPT")
+
+	tag, err := ParseReader(buf, Options{Parse: true, FrameParsers: map[string]FrameParser{"XOPT": parseVendorFrame}})
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+	testVendorFrame(t, tag, "XOPT")
+}
+
+func TestRegisterFrameParser(t *testing.T) {
+	t.Parallel()
+
+	RegisterFrameParser("XREG", parseVendorFrame)
+	defer RegisterFrameParser("XREG", nil)
+
+	tag, err := ParseReader(writeVendorTag(t, "XREG"), parseOpts)
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+	testVendorFrame(t, tag, "XREG")
+
+	RegisterFrameParser("XREG", nil)
+	tag, err = ParseReader(writeVendorTag(t, "XREG"), parseOpts)
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+	if _, ok := tag.GetLastFrame("XREG").(UnknownFrame); !ok {
+		t.Errorf("Expected UnknownFrame after unregistering parser, got %T", tag.GetLastFrame("XREG"))
+	}
+}
+
+func TestCustomParserOfTextFrame(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.SetGenre("Rock")
+	tag.AddTextFrame("TDRC", EncodingUTF8, "2016")
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsers := map[string]FrameParser{"TIT2": parseVendorFrame, "TCON": parseVendorFrame, "TDRC": parseVendorFrame}
+	parsed, err := ParseReader(buf, Options{Parse: true, FrameParsers: parsers})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Frames, which are not TextFrame, must not cause panic.
+	if title := parsed.Title(); title != "" {
+		t.Errorf("Expected blank title, got %q", title)
+	}
+	if genres := parsed.Genres(); genres != nil {
+		t.Errorf("Expected no genres, got %q", genres)
+	}
+	if ts, err := parsed.Timestamp("TDRC"); err != nil || !ts.IsZero() {
+		t.Errorf("Expected zero timestamp without error, got %v and %v", ts, err)
+	}
+	parsed.ConvertVersion(3)
+	if _, ok := parsed.GetLastFrame("TCON").(vendorFrame); !ok {
+		t.Errorf("Expected vendorFrame in TCON, got %T", parsed.GetLastFrame("TCON"))
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parsePosition parses text of frames like TRCK and TPOS in format
+// "n/total" and returns n and total. It's tolerant to whitespace,
+// leading zeros and missing total. Blank or invalid parts are returned as 0.
+func parsePosition(text string) (n, total int) {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, '/'); i >= 0 {
+		total = parsePositionNumber(text[i+1:])
+		text = text[:i]
+	}
+	return parsePositionNumber(text), total
+}
+
+func parsePositionNumber(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// formatPosition formats n and total for frames like TRCK and TPOS.
+// If total is not positive, it's omitted.
+func formatPosition(n, total int) string {
+	if total <= 0 {
+		return strconv.Itoa(n)
+	}
+	return strconv.Itoa(n) + "/" + strconv.Itoa(total)
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import "testing"
+
+func TestParsePosition(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text     string
+		n, total int
+	}{
+		{"", 0, 0},
+		{"3", 3, 0},
+		{"3/12", 3, 12},
+		{"03/012", 3, 12},
+		{" 3 / 12 ", 3, 12},
+		{"/12", 0, 12},
+		{"3/", 3, 0},
+		{"A/B", 0, 0},
+	}
+
+	for _, tc := range testCases {
+		n, total := parsePosition(tc.text)
+		if n != tc.n || total != tc.total {
+			t.Errorf("parsePosition(%q): expected %v and %v, got %v and %v", tc.text, tc.n, tc.total, n, total)
+		}
+	}
+}
+
+func TestTrackAndDiscNumber(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTrackNumber(3, 12)
+	tag.SetDiscNumber(1, 0)
+
+	if got := tag.GetTextFrame("TRCK").Text; got != "3/12" {
+		t.Errorf("Expected TRCK %q, got %q", "3/12", got)
+	}
+	if got := tag.GetTextFrame("TPOS").Text; got != "1" {
+		t.Errorf("Expected TPOS %q, got %q", "1", got)
+	}
+
+	if track, total := tag.TrackNumber(); track != 3 || total != 12 {
+		t.Errorf("Expected track 3 of 12, got %v of %v", track, total)
+	}
+	if disc, total := tag.DiscNumber(); disc != 1 || total != 0 {
+		t.Errorf("Expected disc 1 of 0, got %v of %v", disc, total)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+)
+
+// readRawFrame reads the frame body from bodyRd and returns it together
+// with the frame header. If bodyRd ends before the whole body is read,
+// the returned slice contains only read bytes.
+func readRawFrame(header []byte, bodyRd *io.LimitedReader) ([]byte, error) {
+	raw := make([]byte, len(header)+int(bodyRd.N))
+	copy(raw, header)
+
+	n, err := io.ReadFull(bodyRd, raw[len(header):])
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return raw[:len(header)+n], err
+}
+
+// completeRawFrame returns raw, if it contains the whole frame with body
+// of bodySize bytes, otherwise nil.
+func completeRawFrame(raw []byte, bodySize int64) []byte {
+	if int64(len(raw)-frameHeaderSize) != bodySize {
+		return nil
+	}
+	return raw
+}
+
+// fixRawFrameHeaderSize writes bodySize to frame header fh as synchsafe
+// integer, so the original frame with non-synchsafe size in ID3v2.4 tag
+// is written back with the size, which other frames of tag will have.
+func fixRawFrameHeaderSize(fh []byte, bodySize int64) {
+	buf := new(bytes.Buffer)
+	bw := newBufWriter(buf)
+	// Body size can't overflow, because it's not greater than tag size.
+	bw.WriteBytesSize(uint(bodySize), true)
+	bw.Flush()
+	copy(fh[4:8], buf.Bytes())
+}
+
+// setRawFrame keeps raw as original data of frame with id, which is not
+// in sequence. If raw is nil, the original data is deleted.
+func (tag *Tag) setRawFrame(id string, raw []byte) {
+	if raw == nil {
+		delete(tag.rawFrames, id)
+		return
+	}
+	if tag.rawFrames == nil {
+		tag.rawFrames = make(map[string][]byte)
+	}
+	tag.rawFrames[id] = raw
+}
+
+// deleteAllRawFrames deletes the original data of all frames in tag.
+func (tag *Tag) deleteAllRawFrames() {
+	tag.rawFrames = nil
+	for _, s := range tag.sequences {
+		s.raws = nil
+	}
+}
+
+// idFrameToWrite returns frame f with id and its original data raw
+// how they will be written by WriteTo. raw is dropped, if f is changed
+// by restrictions.
+func (tag *Tag) idFrameToWrite(id string, f Framer, raw []byte) idFrame {
+	written := tag.frameToWrite(id, f)
+	if raw != nil && tag.activeRestrictions() != nil && !reflect.DeepEqual(written, f) {
+		raw = nil
+	}
+	return idFrame{id: id, frame: written, raw: raw}
+}
+
+// size returns the size of frame including frame header,
+// how it will be written by WriteTo.
+func (f idFrame) size() int {
+	if f.raw != nil {
+		return len(f.raw)
+	}
+	return frameHeaderSize + f.frame.Size()
+}
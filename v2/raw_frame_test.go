@@ -0,0 +1,144 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestKeepRawFrames writes a tag with frames, which can't be written
+// by id3v2 the same way (frame flags, ISO text with extra null bytes,
+// chapter with unsupported subframe), and checks if they're written back
+// byte-for-byte identical with Options.KeepRawFrames.
+func TestKeepRawFrames(t *testing.T) {
+	t.Parallel()
+
+	frames := [][]byte{
+		// TIT2 with frame flags.
+		{'T', 'I', 'T', '2', 0, 0, 0, 6, 0x40, 0x00, 0, 'T', 'i', 't', 'l', 'e'},
+		// TALB with two terminating null bytes.
+		{'T', 'A', 'L', 'B', 0, 0, 0, 7, 0, 0, 0, 'A', 'l', 'b', 'u', 'm', 0},
+		// CHAP with TPE1 subframe.
+		{'C', 'H', 'A', 'P', 0, 0, 0, 32, 0, 0,
+			'c', 'h', '1', 0, 0, 0, 0, 0, 0, 0, 0, 1, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+			'T', 'P', 'E', '1', 0, 0, 0, 2, 0, 0, 0, 'A'},
+	}
+
+	var framesData []byte
+	for _, f := range frames {
+		framesData = append(framesData, f...)
+	}
+
+	buf := new(bytes.Buffer)
+	bw := newBufWriter(buf)
+	writeTagHeader(bw, uint(len(framesData)), 4)
+	bw.Write(framesData)
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	original := append([]byte{}, buf.Bytes()...)
+
+	tag, err := ParseReader(buf, Options{Parse: true, KeepRawFrames: true})
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+	if tag.Title() != "Title" || tag.Album() != "Album" {
+		t.Errorf("Expected title %q and album %q, got %q and %q", "Title", "Album", tag.Title(), tag.Album())
+	}
+
+	// Change only title, all other frames should be untouched.
+	tag.SetTitle("New title")
+
+	written := new(bytes.Buffer)
+	n, err := tag.WriteTo(written)
+	if err != nil {
+		t.Fatal("Error by writing tag:", err)
+	}
+	if n != int64(tag.Size()) {
+		t.Errorf("Expected WriteTo n==%v, got %v", tag.Size(), n)
+	}
+
+	for _, f := range frames[1:] {
+		if !bytes.Contains(written.Bytes(), f) {
+			t.Errorf("Expected untouched frame %q to be written byte-for-byte", f[:4])
+		}
+	}
+	if bytes.Contains(written.Bytes(), frames[0]) {
+		t.Error("Changed title frame should not be written as original")
+	}
+
+	// Without changes the whole tag should be identical.
+	tag, err = ParseReader(bytes.NewReader(original), Options{Parse: true, KeepRawFrames: true})
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+	written.Reset()
+	if _, err := tag.WriteTo(written); err != nil {
+		t.Fatal("Error by writing tag:", err)
+	}
+	if written.Len() != len(original) {
+		t.Errorf("Expected tag of %v bytes, got %v", len(original), written.Len())
+	}
+	for _, f := range frames {
+		if !bytes.Contains(written.Bytes(), f) {
+			t.Errorf("Expected untouched frame %q to be written byte-for-byte", f[:4])
+		}
+	}
+}
+
+func TestKeepRawFramesNonSynchSafeSize(t *testing.T) {
+	t.Parallel()
+
+	data := writeITunesTag(t, 199)
+	tag, err := ParseReader(bytes.NewReader(data), Options{Parse: true, KeepRawFrames: true})
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+
+	written := new(bytes.Buffer)
+	if _, err := tag.WriteTo(written); err != nil {
+		t.Fatal("Error by writing tag:", err)
+	}
+	parsed, err := ParseReader(written, parseOpts)
+	if err != nil {
+		t.Fatal("Error by parsing written tag:", err)
+	}
+	if len(parsed.Title()) != 199 || parsed.Artist() != "Artist" {
+		t.Errorf("Expected title of length %v and artist %q, got %q and %q", 199, "Artist", parsed.Title(), parsed.Artist())
+	}
+	if warnings := parsed.Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+}
+
+func TestKeepRawFramesReplacedFrame(t *testing.T) {
+	t.Parallel()
+
+	// TIT2 with frame flags.
+	title := []byte{'T', 'I', 'T', '2', 0, 0, 0, 6, 0x40, 0x00, 0, 'T', 'i', 't', 'l', 'e'}
+	buf := new(bytes.Buffer)
+	bw := newBufWriter(buf)
+	writeTagHeader(bw, uint(len(title)), 4)
+	bw.Write(title)
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := ParseReader(buf, Options{Parse: true, KeepRawFrames: true})
+	if err != nil {
+		t.Fatal("Error by parsing tag:", err)
+	}
+	// The same value is added, but it's another frame now.
+	tag.AddFrame("TIT2", tag.GetLastFrame("TIT2"))
+
+	written := new(bytes.Buffer)
+	if _, err := tag.WriteTo(written); err != nil {
+		t.Fatal("Error by writing tag:", err)
+	}
+	if bytes.Contains(written.Bytes(), title) {
+		t.Error("Replaced frame should not be written as original")
+	}
+}
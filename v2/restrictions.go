@@ -0,0 +1,367 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRestrictionViolation is returned by WriteTo, if tag violates its
+// restrictions and it can't be fixed automatically.
+var ErrRestrictionViolation = errors.New("tag violates its restrictions")
+
+// TagSizeRestriction restricts the count of frames and the size of tag.
+type TagSizeRestriction byte
+
+const (
+	// TagSize128Frames1MB allows no more than 128 frames and 1 MB total tag size.
+	TagSize128Frames1MB TagSizeRestriction = iota
+	// TagSize64Frames128KB allows no more than 64 frames and 128 KB total tag size.
+	TagSize64Frames128KB
+	// TagSize32Frames40KB allows no more than 32 frames and 40 KB total tag size.
+	TagSize32Frames40KB
+	// TagSize32Frames4KB allows no more than 32 frames and 4 KB total tag size.
+	TagSize32Frames4KB
+)
+
+// Limits returns the maximal count of frames and the maximal size of tag
+// in bytes.
+func (r TagSizeRestriction) Limits() (frames, size int) {
+	switch r {
+	case TagSize64Frames128KB:
+		return 64, 128 * 1024
+	case TagSize32Frames40KB:
+		return 32, 40 * 1024
+	case TagSize32Frames4KB:
+		return 32, 4 * 1024
+	}
+	return 128, 1024 * 1024
+}
+
+// TextFieldSizeRestriction restricts the length of strings in frames.
+type TextFieldSizeRestriction byte
+
+const (
+	TextFieldSizeUnrestricted TextFieldSizeRestriction = iota
+	// TextFieldSize1024 allows no strings longer than 1024 characters.
+	TextFieldSize1024
+	// TextFieldSize128 allows no strings longer than 128 characters.
+	TextFieldSize128
+	// TextFieldSize30 allows no strings longer than 30 characters.
+	TextFieldSize30
+)
+
+// MaxLength returns the maximal count of characters in string.
+// It returns 0, if the length is unrestricted.
+func (r TextFieldSizeRestriction) MaxLength() int {
+	switch r {
+	case TextFieldSize1024:
+		return 1024
+	case TextFieldSize128:
+		return 128
+	case TextFieldSize30:
+		return 30
+	}
+	return 0
+}
+
+// ImageSizeRestriction restricts the size of pictures.
+type ImageSizeRestriction byte
+
+const (
+	ImageSizeUnrestricted ImageSizeRestriction = iota
+	// ImageSize256 allows only pictures of 256x256 pixels or smaller.
+	ImageSize256
+	// ImageSize64 allows only pictures of 64x64 pixels or smaller.
+	ImageSize64
+	// ImageSizeExactly64 allows only pictures of exactly 64x64 pixels.
+	ImageSizeExactly64
+)
+
+// Restrictions are restrictions of ID3v2.4 tag declared in its extended
+// header. They are needed e.g. by devices with strict limits.
+type Restrictions struct {
+	TagSize TagSizeRestriction
+
+	// TextEncoding allows only ISO-8859-1 and UTF-8 encodings.
+	TextEncoding bool
+
+	TextFieldSize TextFieldSizeRestriction
+
+	// ImageEncoding allows only PNG and JPEG pictures.
+	ImageEncoding bool
+
+	ImageSize ImageSizeRestriction
+}
+
+// parseRestrictions parses restrictions from b in format %ppqrrstt.
+func parseRestrictions(b byte) Restrictions {
+	return Restrictions{
+		TagSize:       TagSizeRestriction(b >> 6),
+		TextEncoding:  b&(1<<5) != 0,
+		TextFieldSize: TextFieldSizeRestriction(b >> 3 & 3),
+		ImageEncoding: b&(1<<2) != 0,
+		ImageSize:     ImageSizeRestriction(b & 3),
+	}
+}
+
+func (r Restrictions) byte() byte {
+	b := byte(r.TagSize&3)<<6 | byte(r.TextFieldSize&3)<<3 | byte(r.ImageSize&3)
+	if r.TextEncoding {
+		b |= 1 << 5
+	}
+	if r.ImageEncoding {
+		b |= 1 << 2
+	}
+	return b
+}
+
+// Restrictions returns restrictions of tag. ok is false, if tag has
+// no restrictions.
+func (tag *Tag) Restrictions() (r Restrictions, ok bool) {
+	if tag.restrictions == nil {
+		return Restrictions{}, false
+	}
+	return *tag.restrictions, true
+}
+
+// SetRestrictions sets restrictions of tag, which are written in extended
+// header of ID3v2.4 tag. If r is nil, restrictions are deleted.
+//
+// WriteTo enforces restrictions: texts are reencoded with UTF-8 and strings
+// are truncated, if it's needed. Count of frames, size of tag and pictures
+// are not fixed automatically: if they violate restrictions, WriteTo
+// returns ErrRestrictionViolation and writes nothing, so frames must be
+// deleted or pictures must be replaced by caller.
+// Restrictions are ignored in ID3v2.3 tags.
+func (tag *Tag) SetRestrictions(r *Restrictions) {
+	if r == nil {
+		tag.restrictions = nil
+		return
+	}
+	restrictions := *r
+	tag.restrictions = &restrictions
+}
+
+// activeRestrictions returns restrictions, which are considered by WriteTo.
+func (tag *Tag) activeRestrictions() *Restrictions {
+	if tag.version != 4 {
+		return nil
+	}
+	return tag.restrictions
+}
+
+// frameToWrite returns frame f with id how it will be written by WriteTo.
+func (tag *Tag) frameToWrite(id string, f Framer) Framer {
+	if r := tag.activeRestrictions(); r != nil {
+		return r.apply(id, f)
+	}
+	return f
+}
+
+// apply reencodes texts of f and truncates its strings in accordance
+// with restrictions.
+func (r Restrictions) apply(id string, f Framer) Framer {
+	switch f := f.(type) {
+	case TextFrame:
+		return r.applyToTextFrame(f)
+	case CommentFrame:
+		f.Encoding = r.encoding(f.Encoding)
+		f.Description = r.truncate(f.Description)
+		f.Text = r.truncate(f.Text)
+		return f
+	case PictureFrame:
+		f.Encoding = r.encoding(f.Encoding)
+		f.Description = r.truncate(f.Description)
+		return f
+	case UnsynchronisedLyricsFrame:
+		f.Encoding = r.encoding(f.Encoding)
+		f.ContentDescriptor = r.truncate(f.ContentDescriptor)
+		f.Lyrics = r.truncate(f.Lyrics)
+		return f
+	case UserDefinedTextFrame:
+		f.Encoding = r.encoding(f.Encoding)
+		f.Description = r.truncate(f.Description)
+		f.Value = r.truncate(f.Value)
+		return f
+	case ChapterFrame:
+		if f.Title != nil {
+			title := r.applyToTextFrame(*f.Title)
+			f.Title = &title
+		}
+		if f.Description != nil {
+			description := r.applyToTextFrame(*f.Description)
+			f.Description = &description
+		}
+		return f
+	}
+	return f
+}
+
+func (r Restrictions) applyToTextFrame(tf TextFrame) TextFrame {
+	tf.Encoding = r.encoding(tf.Encoding)
+	if r.TextFieldSize != TextFieldSizeUnrestricted && tf.hasMultipleValues() {
+		values := tf.Values()
+		for i := range values {
+			values[i] = r.truncate(values[i])
+		}
+		tf.Text = strings.Join(values, textValuesSeparator)
+	} else {
+		tf.Text = r.truncate(tf.Text)
+	}
+	return tf
+}
+
+func (r Restrictions) encoding(encoding Encoding) Encoding {
+	if r.TextEncoding && !encoding.Equals(EncodingISO) {
+		return EncodingUTF8
+	}
+	return encoding
+}
+
+// truncate truncates s to the maximal length of strings.
+func (r Restrictions) truncate(s string) string {
+	max := r.TextFieldSize.MaxLength()
+	if max == 0 || len(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+// checkRestrictions checks if tag, which frames are restricted,
+// violates restrictions, which can't be fixed automatically, i.e. count
+// of frames, size of tag and encoding and size of pictures.
+// It returns error wrapping ErrRestrictionViolation for the first violation.
+func (tag *Tag) checkRestrictions() error {
+	r := tag.activeRestrictions()
+	if r == nil {
+		return nil
+	}
+
+	maxFrames, maxSize := r.TagSize.Limits()
+	if count := tag.Count(); count > maxFrames {
+		return fmt.Errorf("%w: %d frames, but only %d are allowed", ErrRestrictionViolation, count, maxFrames)
+	}
+	if size := tag.Size(); size > maxSize {
+		return fmt.Errorf("%w: tag size is %d bytes, but only %d are allowed", ErrRestrictionViolation, size, maxSize)
+	}
+
+	for _, f := range tag.GetFrames(tag.CommonID("Attached picture")) {
+		pf, ok := f.(PictureFrame)
+		if !ok {
+			continue
+		}
+		if err := r.checkPicture(pf); err != nil {
+			return fmt.Errorf("%w: picture %q: %v", ErrRestrictionViolation, pf.Description, err)
+		}
+	}
+
+	return nil
+}
+
+func (r Restrictions) checkPicture(pf PictureFrame) error {
+	isPNG, isJPEG := pf.MimeType == "image/png", pf.MimeType == "image/jpeg"
+	if r.ImageEncoding && !isPNG && !isJPEG {
+		return fmt.Errorf("only PNG and JPEG are allowed, got %q", pf.MimeType)
+	}
+	if r.ImageSize == ImageSizeUnrestricted {
+		return nil
+	}
+
+	var width, height int
+	var err error
+	switch {
+	case isPNG:
+		width, height, err = pngSize(pf.Picture)
+	case isJPEG:
+		width, height, err = jpegSize(pf.Picture)
+	default:
+		err = fmt.Errorf("unsupported MIME type %q", pf.MimeType)
+	}
+	if err != nil {
+		return fmt.Errorf("can't get image size: %v", err)
+	}
+
+	switch r.ImageSize {
+	case ImageSize256:
+		if width > 256 || height > 256 {
+			return fmt.Errorf("image size is %dx%d, but only 256x256 or smaller is allowed", width, height)
+		}
+	case ImageSize64:
+		if width > 64 || height > 64 {
+			return fmt.Errorf("image size is %dx%d, but only 64x64 or smaller is allowed", width, height)
+		}
+	case ImageSizeExactly64:
+		if width != 64 || height != 64 {
+			return fmt.Errorf("image size is %dx%d, but only 64x64 is allowed", width, height)
+		}
+	}
+	return nil
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngSize returns the size of PNG image from its IHDR chunk,
+// which must be the first chunk after signature.
+func pngSize(b []byte) (width, height int, err error) {
+	// Signature, length and type of chunk, width and height.
+	if len(b) < 24 || !bytes.HasPrefix(b, pngSignature) || string(b[12:16]) != "IHDR" {
+		return 0, 0, errors.New("invalid PNG header")
+	}
+	return int(binary.BigEndian.Uint32(b[16:20])), int(binary.BigEndian.Uint32(b[20:24])), nil
+}
+
+// jpegSize returns the size of JPEG image from its SOFn marker.
+func jpegSize(b []byte) (width, height int, err error) {
+	if len(b) < 2 || b[0] != 0xFF || b[1] != 0xD8 {
+		return 0, 0, errors.New("invalid JPEG header")
+	}
+
+	for i := 2; i+1 < len(b); {
+		if b[i] != 0xFF {
+			return 0, 0, errors.New("invalid JPEG marker")
+		}
+		marker := b[i+1]
+		i += 2
+		switch {
+		case marker == 0xFF:
+			// Fill byte.
+			i--
+			continue
+		case marker == 0x01 || marker >= 0xD0 && marker <= 0xD7:
+			// Markers without segment.
+			continue
+		case marker == 0xD9 || marker == 0xDA:
+			// End of image or start of scan. No size was found.
+			return 0, 0, errors.New("no SOF marker in JPEG")
+		}
+
+		if i+2 > len(b) {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(b[i:]))
+		if length < 2 || i+length > len(b) {
+			break
+		}
+		// SOF0-SOF15 except DHT, JPG and DAC.
+		if marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC {
+			// Length, precision, height and width.
+			if length < 7 {
+				break
+			}
+			return int(binary.BigEndian.Uint16(b[i+5:])), int(binary.BigEndian.Uint16(b[i+3:])), nil
+		}
+		i += length
+	}
+	return 0, 0, errors.New("unexpected end of JPEG")
+}
@@ -0,0 +1,247 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRestrictionsByte(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 256; i++ {
+		if got := parseRestrictions(byte(i)).byte(); got != byte(i) {
+			t.Errorf("Expected %08b, got %08b", i, got)
+		}
+	}
+}
+
+func TestRestrictionsWriteAndParse(t *testing.T) {
+	t.Parallel()
+
+	restrictions := Restrictions{
+		TagSize:       TagSize64Frames128KB,
+		TextEncoding:  true,
+		TextFieldSize: TextFieldSize30,
+	}
+
+	tag := NewEmptyTag()
+	tag.SetRestrictions(&restrictions)
+	tag.AddTextFrame("TIT2", EncodingUTF16, strings.Repeat("Ü", 40))
+	tag.SetArtist("Artist")
+
+	buf := new(bytes.Buffer)
+	n, err := tag.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(n) != tag.Size() {
+		t.Errorf("Expected written %v bytes, got %v", tag.Size(), n)
+	}
+
+	parsed, err := ParseReader(buf, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := parsed.Restrictions()
+	if !ok || got != restrictions {
+		t.Errorf("Expected restrictions %+v, got %+v", restrictions, got)
+	}
+	title := parsed.GetTextFrame("TIT2")
+	if title.Text != strings.Repeat("Ü", 30) {
+		t.Errorf("Expected title truncated to 30 characters, got %q", title.Text)
+	}
+	if !title.Encoding.Equals(EncodingUTF8) {
+		t.Errorf("Expected encoding %v, got %v", EncodingUTF8, title.Encoding)
+	}
+	if parsed.Artist() != "Artist" {
+		t.Errorf("Expected artist %q, got %q", "Artist", parsed.Artist())
+	}
+
+	// Restrictions aren't written in ID3v2.3 tag.
+	parsed.SetVersion(3)
+	buf.Reset()
+	if _, err := parsed.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Bytes()[5]&flagExtendedHeader != 0 {
+		t.Error("Expected no extended header in ID3v2.3 tag")
+	}
+}
+
+func makePNG(t *testing.T, width, height int) []byte {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeJPEG(t *testing.T, width, height int) []byte {
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, image.NewGray(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageSize(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range [][2]int{{1, 1}, {64, 64}, {100, 50}, {300, 1000}} {
+		width, height := size[0], size[1]
+		if w, h, err := pngSize(makePNG(t, width, height)); err != nil || w != width || h != height {
+			t.Errorf("PNG: expected %dx%d, got %dx%d (error %v)", width, height, w, h, err)
+		}
+		if w, h, err := jpegSize(makeJPEG(t, width, height)); err != nil || w != width || h != height {
+			t.Errorf("JPEG: expected %dx%d, got %dx%d (error %v)", width, height, w, h, err)
+		}
+	}
+
+	for _, b := range [][]byte{nil, []byte("GIF89a"), makePNG(t, 64, 64)[:20]} {
+		if _, _, err := pngSize(b); err == nil {
+			t.Errorf("PNG: expected error for %q", b)
+		}
+	}
+	for _, b := range [][]byte{nil, []byte("GIF89a"), {0xFF, 0xD8, 0xFF, 0xC0, 0, 17}, {0xFF, 0xD8, 0xFF, 0xDA}} {
+		if _, _, err := jpegSize(b); err == nil {
+			t.Errorf("JPEG: expected error for %q", b)
+		}
+	}
+}
+
+func TestRestrictionsViolation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		restrictions Restrictions
+		picture      PictureFrame
+		violated     bool
+	}{
+		{
+			name:         "image encoding",
+			restrictions: Restrictions{ImageEncoding: true},
+			picture:      PictureFrame{Encoding: EncodingISO, MimeType: "image/gif", Picture: []byte("GIF89a")},
+			violated:     true,
+		},
+		{
+			name:         "image size",
+			restrictions: Restrictions{ImageSize: ImageSize64},
+			picture:      PictureFrame{Encoding: EncodingISO, MimeType: "image/png", Picture: makePNG(t, 100, 50)},
+			violated:     true,
+		},
+		{
+			name:         "exact image size",
+			restrictions: Restrictions{ImageSize: ImageSizeExactly64},
+			picture:      PictureFrame{Encoding: EncodingISO, MimeType: "image/png", Picture: makePNG(t, 64, 64)},
+		},
+		{
+			name:         "not exact image size",
+			restrictions: Restrictions{ImageSize: ImageSizeExactly64},
+			picture:      PictureFrame{Encoding: EncodingISO, MimeType: "image/png", Picture: makePNG(t, 64, 63)},
+			violated:     true,
+		},
+		{
+			name:         "exact JPEG image size",
+			restrictions: Restrictions{ImageSize: ImageSizeExactly64},
+			picture:      PictureFrame{Encoding: EncodingISO, MimeType: "image/jpeg", Picture: makeJPEG(t, 64, 64)},
+		},
+		{
+			name:         "JPEG image size",
+			restrictions: Restrictions{ImageSize: ImageSizeExactly64},
+			picture:      PictureFrame{Encoding: EncodingISO, MimeType: "image/jpeg", Picture: makeJPEG(t, 32, 32)},
+			violated:     true,
+		},
+		{
+			name:         "tag size",
+			restrictions: Restrictions{TagSize: TagSize32Frames4KB},
+			picture:      PictureFrame{Encoding: EncodingISO, MimeType: "image/png", Picture: make([]byte, 4*1024)},
+			violated:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tag := NewEmptyTag()
+		tag.SetRestrictions(&tc.restrictions)
+		tag.AddAttachedPicture(tc.picture)
+
+		_, err := tag.WriteTo(new(bytes.Buffer))
+		if tc.violated && !errors.Is(err, ErrRestrictionViolation) {
+			t.Errorf("%v: expected %v, got %v", tc.name, ErrRestrictionViolation, err)
+		}
+		if !tc.violated && err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestRestrictionsTagSizeViolation(t *testing.T) {
+	t.Parallel()
+
+	restrictions := Restrictions{TagSize: TagSize32Frames4KB}
+
+	// Tag is bigger than 4 KB.
+	tag := NewEmptyTag()
+	tag.SetRestrictions(&restrictions)
+	tag.AddCommentFrame(CommentFrame{Encoding: EncodingISO, Language: "eng", Text: strings.Repeat("A", 4*1024)})
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); !errors.Is(err, ErrRestrictionViolation) {
+		t.Errorf("Expected %v for tag size %v, got %v", ErrRestrictionViolation, tag.Size(), err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written, got %v bytes", buf.Len())
+	}
+
+	// Violation is not fixed automatically, so tag can be written
+	// only after deleting of frames.
+	tag.DeleteFrames("COMM")
+	tag.SetTitle("Title")
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Errorf("Expected no error after deleting of frames, got %v", err)
+	}
+
+	// Tag has more than 32 frames.
+	tag = NewEmptyTag()
+	tag.SetRestrictions(&restrictions)
+	for i := 0; i < 33; i++ {
+		tag.AddCommentFrame(CommentFrame{Encoding: EncodingISO, Language: "eng", Description: strconv.Itoa(i)})
+	}
+	if _, err := tag.WriteTo(new(bytes.Buffer)); !errors.Is(err, ErrRestrictionViolation) {
+		t.Errorf("Expected %v for %v frames, got %v", ErrRestrictionViolation, tag.Count(), err)
+	}
+}
+
+func TestParseV23ExtendedHeader(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	bw := newBufWriter(buf)
+	writeTagHeaderFlags(bw, 10+16, 3, flagExtendedHeader)
+	bw.Write([]byte{0, 0, 0, 6, 0, 0, 0, 0, 0, 0})                       // extended header
+	bw.Write([]byte{0x54, 0x49, 0x54, 0x32, 00, 00, 00, 06, 00, 00, 00}) // header and encoding
+	bw.WriteString("Title")
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := ParseReader(buf, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tag.Title())
+	}
+	if len(tag.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got %v", tag.Warnings())
+	}
+}
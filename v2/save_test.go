@@ -0,0 +1,240 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+var music = []byte{255, 251, 80, 0, 85, 85, 85}
+
+func makeSourceWithMusic(t *testing.T) []byte {
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.AddAttachedPicture(frontCover)
+
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	buf.Write(music)
+	return buf.Bytes()
+}
+
+func testSavedWithMusic(t *testing.T, saved []byte) {
+	tag, err := ParseReader(bytes.NewReader(saved), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Title() != "New title" {
+		t.Errorf("Expected title %q, got %q", "New title", tag.Title())
+	}
+	if !bytes.Equal(saved[tag.originalSize:], music) {
+		t.Errorf("Expected music %v, got %v", music, saved[tag.originalSize:])
+	}
+}
+
+func TestSaveToWriter(t *testing.T) {
+	t.Parallel()
+
+	source := makeSourceWithMusic(t)
+	original := append([]byte{}, source...)
+
+	tag, err := ParseReader(bytes.NewReader(source), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag.SetTitle("New title")
+
+	buf := new(bytes.Buffer)
+	if err := tag.SaveToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	testSavedWithMusic(t, buf.Bytes())
+
+	if !bytes.Equal(source, original) {
+		t.Error("Source must be left untouched")
+	}
+}
+
+func TestSaveTo(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "id3v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sourcePath := filepath.Join(dir, "source.mp3")
+	source := makeSourceWithMusic(t)
+	if err := ioutil.WriteFile(sourcePath, source, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := Open(sourcePath, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tag.Close()
+	tag.SetTitle("New title")
+
+	newPath := filepath.Join(dir, "new.mp3")
+	if err := tag.SaveTo(newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	testSavedWithMusic(t, mustReadFile(newPath))
+	if !bytes.Equal(mustReadFile(sourcePath), source) {
+		t.Error("Source file must be left untouched")
+	}
+	stat, err := os.Stat(newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode() != 0640 {
+		t.Errorf("Expected mode %v of new file, got %v", os.FileMode(0640), stat.Mode())
+	}
+}
+
+func TestSaveToWriterErrors(t *testing.T) {
+	t.Parallel()
+
+	if err := NewEmptyTag().SaveToWriter(new(bytes.Buffer)); err != ErrNoFile {
+		t.Errorf("Expected %v, got %v", ErrNoFile, err)
+	}
+
+	tag, err := ParseReader(bytes.NewBuffer(makeSourceWithMusic(t)), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tag.SaveToWriter(new(bytes.Buffer)); err != ErrUnseekableReader {
+		t.Errorf("Expected %v, got %v", ErrUnseekableReader, err)
+	}
+}
+
+func writeSourceFile(t *testing.T) (dir, path string) {
+	dir, err := ioutil.TempDir("", "id3v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path = filepath.Join(dir, "source.mp3")
+	if err := ioutil.WriteFile(path, makeSourceWithMusic(t), 0640); err != nil {
+		t.Fatal(err)
+	}
+	return dir, path
+}
+
+func testOnlyFileInDir(t *testing.T, dir, name string) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || infos[0].Name() != name {
+		var names []string
+		for _, info := range infos {
+			names = append(names, info.Name())
+		}
+		t.Errorf("Expected only %q in directory, got %v", name, names)
+	}
+}
+
+func TestSavePreserveTimes(t *testing.T) {
+	t.Parallel()
+
+	dir, path := writeSourceFile(t)
+	defer os.RemoveAll(dir)
+
+	mtime := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err := Open(path, Options{Parse: true, PreserveTimes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tag.Close()
+	tag.SetTitle("New title")
+	if err := tag.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stat.ModTime().Equal(mtime) {
+		t.Errorf("Expected modification time %v, got %v", mtime, stat.ModTime())
+	}
+	if stat.Mode() != 0640 {
+		t.Errorf("Expected mode %v, got %v", os.FileMode(0640), stat.Mode())
+	}
+	testSavedWithMusic(t, mustReadFile(path))
+	testOnlyFileInDir(t, dir, "source.mp3")
+}
+
+func TestSaveRemovesTempFileOnError(t *testing.T) {
+	t.Parallel()
+
+	dir, path := writeSourceFile(t)
+	defer os.RemoveAll(dir)
+
+	tag, err := Open(path, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tag.Close()
+
+	// Make writing of tag fail.
+	tag.AddCommentFrame(CommentFrame{Encoding: EncodingUTF8, Language: "invalid", Text: "Text"})
+	if err := tag.Save(); err != ErrInvalidLanguageLength {
+		t.Errorf("Expected %v, got %v", ErrInvalidLanguageLength, err)
+	}
+	if err := tag.SaveTo(filepath.Join(dir, "new.mp3")); err != ErrInvalidLanguageLength {
+		t.Errorf("Expected %v, got %v", ErrInvalidLanguageLength, err)
+	}
+	testOnlyFileInDir(t, dir, "source.mp3")
+}
+
+func TestConcurrentSaves(t *testing.T) {
+	t.Parallel()
+
+	dir, path := writeSourceFile(t)
+	defer os.RemoveAll(dir)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tag, err := Open(path, parseOpts)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer tag.Close()
+			tag.SetTitle("New title")
+			errs <- tag.Save()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	testSavedWithMusic(t, mustReadFile(path))
+	testOnlyFileInDir(t, dir, "source.mp3")
+}
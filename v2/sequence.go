@@ -4,55 +4,98 @@
 
 package id3v2
 
-import (
-	"sync"
-)
+import "bytes"
 
 // sequence is used to manipulate with frames, which can be in tag
 // more than one (e.g. APIC, COMM, USLT and etc.)
+//
+// sequence owns its frames: they are never shared with callers,
+// so slices returned by Frames stay valid after the sequence
+// is changed or deleted.
 type sequence struct {
 	frames []Framer
+
+	// raws are original data of frames kept with Options.KeepRawFrames.
+	// raws[i] belongs to frames[i]. raws may be shorter than frames,
+	// if the last frames have no original data.
+	raws [][]byte
 }
 
 func (s *sequence) AddFrame(f Framer) {
+	s.addFrame(f, nil)
+}
+
+// addFrame adds f with its original data raw, which may be nil.
+func (s *sequence) addFrame(f Framer, raw []byte) {
 	i := indexOfFrame(f, s.frames)
 
 	if i == -1 {
+		i = len(s.frames)
 		s.frames = append(s.frames, f)
 	} else {
 		s.frames[i] = f
 	}
+	s.setRaw(i, raw)
+}
+
+// raw returns the original data of i-th frame or nil, if there is no one.
+func (s *sequence) raw(i int) []byte {
+	if i < len(s.raws) {
+		return s.raws[i]
+	}
+	return nil
+}
+
+func (s *sequence) setRaw(i int, raw []byte) {
+	if i >= len(s.raws) {
+		if raw == nil {
+			return
+		}
+		s.raws = append(s.raws, make([][]byte, i+1-len(s.raws))...)
+	}
+	s.raws[i] = raw
 }
 
 func indexOfFrame(f Framer, fs []Framer) int {
+	// Compare bodies of unknown frames directly instead of their
+	// identifiers, so bodies are not hashed for every frame in sequence.
+	if uf, ok := f.(UnknownFrame); ok {
+		for i, ff := range fs {
+			if uff, ok := ff.(UnknownFrame); ok && bytes.Equal(uf.Body, uff.Body) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	id := f.UniqueIdentifier()
 	for i, ff := range fs {
-		if f.UniqueIdentifier() == ff.UniqueIdentifier() {
+		if id == ff.UniqueIdentifier() {
 			return i
 		}
 	}
 	return -1
 }
 
+// lastFrame returns the last frame in sequence or nil, if it's empty.
+func (s *sequence) lastFrame() Framer {
+	if len(s.frames) == 0 {
+		return nil
+	}
+	return s.frames[len(s.frames)-1]
+}
+
 func (s *sequence) Count() int {
 	return len(s.frames)
 }
 
+// Frames returns a copy of frames in sequence, which is owned by caller.
 func (s *sequence) Frames() []Framer {
-	return s.frames
+	frames := make([]Framer, len(s.frames))
+	copy(frames, s.frames)
+	return frames
 }
 
-var seqPool = sync.Pool{New: func() interface{} {
+func newSequence() *sequence {
 	return &sequence{frames: []Framer{}}
-}}
-
-func getSequence() *sequence {
-	s := seqPool.Get().(*sequence)
-	if s.Count() > 0 {
-		s.frames = []Framer{}
-	}
-	return s
-}
-
-func putSequence(s *sequence) {
-	seqPool.Put(s)
 }
@@ -9,8 +9,7 @@ import "testing"
 func TestSequenceCommentFramesUniqueness(t *testing.T) {
 	t.Parallel()
 
-	s := getSequence()
-	defer putSequence(s)
+	s := newSequence()
 
 	s.AddFrame(CommentFrame{Language: "A", Description: "A"})
 	testSequenceCount(t, s, 1)
@@ -30,8 +29,7 @@ func TestSequenceCommentFramesUniqueness(t *testing.T) {
 func TestSequencePictureFramesUniqueness(t *testing.T) {
 	t.Parallel()
 
-	s := getSequence()
-	defer putSequence(s)
+	s := newSequence()
 
 	s.AddFrame(PictureFrame{Description: "A", PictureType: 0x00})
 	testSequenceCount(t, s, 1)
@@ -59,8 +57,7 @@ func TestSequencePictureFramesUniqueness(t *testing.T) {
 func TestSequenceUSLFsUniqueness(t *testing.T) {
 	t.Parallel()
 
-	s := getSequence()
-	defer putSequence(s)
+	s := newSequence()
 
 	s.AddFrame(UnsynchronisedLyricsFrame{Language: "A", ContentDescriptor: "A"})
 	testSequenceCount(t, s, 1)
@@ -80,8 +77,7 @@ func TestSequenceUSLFsUniqueness(t *testing.T) {
 func TestSequenceUDTFsUniqueness(t *testing.T) {
 	t.Parallel()
 
-	s := getSequence()
-	defer putSequence(s)
+	s := newSequence()
 
 	s.AddFrame(UserDefinedTextFrame{Description: "A"})
 	testSequenceA(t, s)
@@ -124,3 +120,80 @@ func testFrameUniqueIdentifier(t *testing.T, f Framer, expected string) {
 		t.Errorf("Expected frame with unique identifier %v, got %v", expected, got)
 	}
 }
+
+func TestGetFramesAfterDeleteAndAdd(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "A", Text: "A"})
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "B", Text: "B"})
+
+	comments := tag.GetFrames(tag.CommonID("Comments"))
+	all := tag.AllFrames()
+
+	// Deleted sequences must not be reused by frames added later.
+	tag.DeleteFrames(tag.CommonID("Comments"))
+	tag.AddAttachedPicture(PictureFrame{Description: "C", PictureType: PTFrontCover})
+	tag.AddUnsynchronisedLyricsFrame(UnsynchronisedLyricsFrame{Language: "eng", ContentDescriptor: "D"})
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "E", Text: "E"})
+
+	for _, frames := range [][]Framer{comments, all[tag.CommonID("Comments")]} {
+		if len(frames) != 2 {
+			t.Fatalf("Expected 2 frames, got %v", len(frames))
+		}
+		testFrameUniqueIdentifier(t, frames[0], "engA")
+		testFrameUniqueIdentifier(t, frames[1], "engB")
+	}
+}
+
+func TestGetFramesAfterDeleteAllFrames(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "A", Text: "A"})
+	comments := tag.GetFrames(tag.CommonID("Comments"))
+
+	tag.DeleteAllFrames()
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "B", Text: "B"})
+
+	if len(comments) != 1 {
+		t.Fatalf("Expected 1 frame, got %v", len(comments))
+	}
+	testFrameUniqueIdentifier(t, comments[0], "engA")
+}
+
+func TestGetFramesAfterReplace(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "A", Text: "Old"})
+	comments := tag.GetFrames(tag.CommonID("Comments"))
+
+	// Frame with the same unique identifier replaces the old one.
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "A", Text: "New"})
+
+	if text := comments[0].(CommentFrame).Text; text != "Old" {
+		t.Errorf("Expected text %q, got %q", "Old", text)
+	}
+	if text := tag.GetLastFrame(tag.CommonID("Comments")).(CommentFrame).Text; text != "New" {
+		t.Errorf("Expected text %q, got %q", "New", text)
+	}
+}
+
+func TestModifyReturnedFrames(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.AddCommentFrame(CommentFrame{Language: "eng", Description: "A", Text: "A"})
+
+	comments := tag.GetFrames(tag.CommonID("Comments"))
+	comments[0] = CommentFrame{Language: "eng", Description: "B", Text: "B"}
+	_ = append(comments[:0], CommentFrame{Language: "eng", Description: "C", Text: "C"})
+	tag.AllFrames()[tag.CommonID("Comments")][0] = CommentFrame{Language: "eng", Description: "D", Text: "D"}
+
+	frames := tag.GetFrames(tag.CommonID("Comments"))
+	if len(frames) != 1 {
+		t.Fatalf("Expected 1 frame, got %v", len(frames))
+	}
+	testFrameUniqueIdentifier(t, frames[0], "engA")
+}
@@ -0,0 +1,168 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import "io"
+
+// Snapshot is an immutable copy of tag. It's safe for concurrent use
+// by multiple goroutines, also while the original tag is edited.
+//
+// Frames are shared with the original tag, so they must not be modified
+// in place (e.g. bytes of PictureFrame.Picture).
+type Snapshot struct {
+	tag *Tag
+
+	// loadErr is the error of loading lazy frames, which is returned
+	// by WriteTo, because such frames are not in snapshot.
+	loadErr error
+}
+
+// Snapshot returns an immutable copy of frames and settings of tag,
+// which are needed for reading and writing of frames. All lazy frames
+// (see Options.Lazy) are loaded before copying. If some of them can't
+// be loaded, WriteTo of snapshot returns the error of loading.
+//
+// Tag itself is not safe for concurrent use, so Snapshot must not be
+// called concurrently with other methods of tag.
+func (tag *Tag) Snapshot() *Snapshot {
+	loadErr := tag.loadAllLazyFrames()
+
+	clone := &Tag{
+		frames:          make(map[string]Framer, len(tag.frames)),
+		sequences:       make(map[string]*sequence, len(tag.sequences)),
+		defaultEncoding: tag.defaultEncoding,
+		crc:             tag.crc,
+		version:         tag.version,
+	}
+	for id, f := range tag.frames {
+		clone.frames[id] = f
+	}
+	for id, s := range tag.sequences {
+		clone.sequences[id] = &sequence{
+			frames: append([]Framer(nil), s.frames...),
+			raws:   append([][]byte(nil), s.raws...),
+		}
+	}
+	if tag.rawFrames != nil {
+		clone.rawFrames = make(map[string][]byte, len(tag.rawFrames))
+		for id, raw := range tag.rawFrames {
+			clone.rawFrames[id] = raw
+		}
+	}
+	if tag.restrictions != nil {
+		restrictions := *tag.restrictions
+		clone.restrictions = &restrictions
+	}
+
+	return &Snapshot{tag: clone, loadErr: loadErr}
+}
+
+// Version returns current ID3v2 version of tag.
+func (s *Snapshot) Version() byte {
+	return s.tag.Version()
+}
+
+// CommonID returns frame ID from given description considering version
+// of tag. See Tag.CommonID.
+func (s *Snapshot) CommonID(description string) string {
+	return s.tag.CommonID(description)
+}
+
+// Count returns the number of frames in tag.
+func (s *Snapshot) Count() int {
+	return s.tag.Count()
+}
+
+// HasFrames checks if there is at least one frame in tag.
+func (s *Snapshot) HasFrames() bool {
+	return s.tag.HasFrames()
+}
+
+// AllFrames returns map, that contains all frames in tag.
+// The key of this map is an ID of frame and value is an array of frames.
+// The map and slices are owned by caller, like in Tag.AllFrames.
+func (s *Snapshot) AllFrames() map[string][]Framer {
+	return s.tag.AllFrames()
+}
+
+// GetFrames returns frames with corresponding id. The returned slice
+// is owned by caller, like in Tag.GetFrames. It returns nil, if there
+// are no frames.
+func (s *Snapshot) GetFrames(id string) []Framer {
+	return s.tag.GetFrames(id)
+}
+
+// GetLastFrame returns the last frame with corresponding id.
+// See Tag.GetLastFrame.
+func (s *Snapshot) GetLastFrame(id string) Framer {
+	return s.tag.GetLastFrame(id)
+}
+
+// GetTextFrame returns text frame with corresponding id.
+func (s *Snapshot) GetTextFrame(id string) TextFrame {
+	return s.tag.GetTextFrame(id)
+}
+
+func (s *Snapshot) Title() string {
+	return s.tag.Title()
+}
+
+func (s *Snapshot) Artist() string {
+	return s.tag.Artist()
+}
+
+// Artists returns all values of artist frame. See Tag.Artists.
+func (s *Snapshot) Artists() []string {
+	return s.tag.Artists()
+}
+
+func (s *Snapshot) Album() string {
+	return s.tag.Album()
+}
+
+func (s *Snapshot) Year() string {
+	return s.tag.Year()
+}
+
+func (s *Snapshot) Genre() string {
+	return s.tag.Genre()
+}
+
+// Genres returns names of all genres in content type frame.
+// See Tag.Genres.
+func (s *Snapshot) Genres() []string {
+	return s.tag.Genres()
+}
+
+// TrackNumber returns the track number and total number of tracks
+// from track number frame. See Tag.TrackNumber.
+func (s *Snapshot) TrackNumber() (track, total int) {
+	return s.tag.TrackNumber()
+}
+
+// DiscNumber returns the disc number and total number of discs
+// from part of a set frame. See Tag.DiscNumber.
+func (s *Snapshot) DiscNumber() (disc, total int) {
+	return s.tag.DiscNumber()
+}
+
+// Timestamp returns the timestamp of text frame with id.
+// See Tag.Timestamp.
+func (s *Snapshot) Timestamp(id string) (Timestamp, error) {
+	return s.tag.Timestamp(id)
+}
+
+// Size returns the size of tag (tag header + size of all frames) in bytes.
+func (s *Snapshot) Size() int {
+	return s.tag.Size()
+}
+
+// WriteTo writes whole tag in w. See Tag.WriteTo.
+func (s *Snapshot) WriteTo(w io.Writer) (n int64, err error) {
+	if s.loadErr != nil {
+		return 0, s.loadErr
+	}
+	return s.tag.WriteTo(w)
+}
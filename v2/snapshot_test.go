@@ -0,0 +1,111 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotIsIndependent(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.AddCommentFrame(engComm)
+	tag.AddAttachedPicture(frontCover)
+
+	snapshot := tag.Snapshot()
+	expected := snapshot.AllFrames()
+
+	tag.SetTitle("New title")
+	tag.DeleteFrames(tag.CommonID("Comments"))
+	tag.AddCommentFrame(gerComm)
+	tag.AddAttachedPicture(backCover)
+
+	if snapshot.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", snapshot.Title())
+	}
+	comments := snapshot.GetFrames(snapshot.CommonID("Comments"))
+	if len(comments) != 1 || !reflect.DeepEqual(comments[0], engComm) {
+		t.Errorf("Expected comments %v, got %v", []Framer{engComm}, comments)
+	}
+	if count := len(snapshot.GetFrames(snapshot.CommonID("Attached picture"))); count != 1 {
+		t.Errorf("Expected 1 picture, got %v", count)
+	}
+
+	// Slices returned by snapshot must not affect it.
+	comments[0] = gerComm
+	snapshot.AllFrames()[snapshot.CommonID("Comments")][0] = gerComm
+	if comment := snapshot.GetLastFrame(snapshot.CommonID("Comments")); !reflect.DeepEqual(comment, engComm) {
+		t.Errorf("Expected comment %v, got %v", engComm, comment)
+	}
+
+	// Frames are compared by ID and value, because order of written
+	// frames is not defined.
+	if actual := snapshot.AllFrames(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected frames %v, got %v", expected, actual)
+	}
+}
+
+func TestSnapshotConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.AddCommentFrame(engComm)
+	snapshot := tag.Snapshot()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if snapshot.Title() != "Title" {
+					t.Error("Unexpected title of snapshot")
+					return
+				}
+				snapshot.GetFrames(snapshot.CommonID("Comments"))
+				if _, err := snapshot.WriteTo(new(bytes.Buffer)); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	// Edit the original tag meanwhile.
+	for j := 0; j < 100; j++ {
+		tag.DeleteFrames(tag.CommonID("Comments"))
+		tag.AddCommentFrame(gerComm)
+		tag.SetTitle("New title")
+	}
+	wg.Wait()
+}
+
+func TestSnapshotLoadsLazyFrames(t *testing.T) {
+	t.Parallel()
+
+	source := makeSourceWithMusic(t)
+	tag, err := ParseReader(bytes.NewReader(source), Options{Parse: true, Lazy: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := tag.Snapshot()
+	if snapshot.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", snapshot.Title())
+	}
+	pictures := snapshot.GetFrames(snapshot.CommonID("Attached picture"))
+	if len(pictures) != 1 {
+		t.Fatalf("Expected 1 picture, got %v", len(pictures))
+	}
+	if err := comparePictureFrames(pictures[0].(PictureFrame), frontCover); err != nil {
+		t.Error(err)
+	}
+}
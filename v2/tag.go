@@ -5,18 +5,61 @@
 package id3v2
 
 import (
+	"context"
 	"errors"
+	"hash/crc32"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 var ErrNoFile = errors.New("tag was not initialized with file")
+var ErrUnseekableReader = errors.New("reader of tag doesn't implement io.ReadSeeker")
+
+// ErrNoFS is returned by Save and SaveTo, if tag was initialized with File,
+// which is not *os.File, without Options.FS, so filesystem of file is unknown.
+var ErrNoFS = errors.New("tag was initialized with file not from OS, but without Options.FS")
 
 // Tag stores all information about opened tag.
+// Tag is not safe for concurrent use. Use Snapshot to share frames
+// of tag between goroutines.
 type Tag struct {
 	frames    map[string]Framer
 	sequences map[string]*sequence
 
+	// rawFrames are original data of frames in frames map kept with
+	// Options.KeepRawFrames. Original data of frames in sequences
+	// are kept in sequences.
+	rawFrames map[string][]byte
+
+	// lazy keeps frames, which bodies are not parsed yet,
+	// if tag is parsed with Options.Lazy.
+	lazy *lazyLoader
+
+	// warnings are problems found by parsing in lenient mode.
+	warnings []Warning
+
+	// padding is padding of parsed tag, if it's known.
+	padding *Padding
+
+	// restrictions are restrictions of ID3v2.4 tag from extended header.
+	restrictions *Restrictions
+
+	// trailers are tags found at the end of reader by ParseReaderAt.
+	trailers []Trailer
+
+	// crc defines, if CRC-32 of tag data should be written.
+	crc bool
+
+	// preserveTimes defines, if Save should preserve access and
+	// modification times of file.
+	preserveTimes bool
+
+	// fs is the filesystem, which file of tag is opened from.
+	// If it's nil, OSFS is used for *os.File (see saveFS).
+	fs FS
+
 	defaultEncoding Encoding
 	reader          io.Reader
 	originalSize    int64
@@ -30,19 +73,29 @@ type Tag struct {
 // transcription frames, better use AddAttachedPicture, AddCommentFrame
 // or AddUnsynchronisedLyricsFrame methods respectively.
 func (tag *Tag) AddFrame(id string, f Framer) {
+	tag.addFrame(id, f, nil)
+}
+
+// addFrame adds f with id and its original data raw, which may be nil,
+// to tag.
+func (tag *Tag) addFrame(id string, f Framer, raw []byte) {
 	if id == "" || f == nil {
 		return
 	}
 
 	if mustFrameBeInSequence(id) {
+		tag.loadLazyFrames(id)
+
 		sequence := tag.sequences[id]
 		if sequence == nil {
-			sequence = getSequence()
+			sequence = newSequence()
 		}
-		sequence.AddFrame(f)
+		sequence.addFrame(f, raw)
 		tag.sequences[id] = sequence
 	} else {
+		tag.deleteLazyFrames(id)
 		tag.frames[id] = f
+		tag.setRawFrame(id, raw)
 	}
 }
 
@@ -67,6 +120,13 @@ func (tag *Tag) AddTextFrame(id string, encoding Encoding, text string) {
 	tag.AddFrame(id, TextFrame{Encoding: encoding, Text: text})
 }
 
+// AddTextFrameValues creates the text frame with provided encoding and
+// multiple values and adds to tag.
+// Multiple values in one text frame are allowed only in ID3v2.4.
+func (tag *Tag) AddTextFrameValues(id string, encoding Encoding, values ...string) {
+	tag.AddTextFrame(id, encoding, strings.Join(values, textValuesSeparator))
+}
+
 // AddUnsynchronisedLyricsFrame adds the unsynchronised lyrics/text frame
 // to tag.
 func (tag *Tag) AddUnsynchronisedLyricsFrame(uslf UnsynchronisedLyricsFrame) {
@@ -92,8 +152,12 @@ func (tag *Tag) AddUFIDFrame(ufid UFIDFrame) {
 // v2.3: http://id3.org/id3v2.3.0#Declared_ID3v2_frames
 // v2.4: http://id3.org/id3v2.4.0-frames
 func (tag *Tag) CommonID(description string) string {
+	return commonID(description, tag.version)
+}
+
+func commonID(description string, version byte) string {
 	var ids map[string]string
-	if tag.version == 3 {
+	if version == 3 {
 		ids = V23CommonIDs
 	} else {
 		ids = V24CommonIDs
@@ -106,7 +170,11 @@ func (tag *Tag) CommonID(description string) string {
 
 // AllFrames returns map, that contains all frames in tag, that could be parsed.
 // The key of this map is an ID of frame and value is an array of frames.
+// The map and slices are owned by caller and aren't affected by later
+// changes of tag.
 func (tag *Tag) AllFrames() map[string][]Framer {
+	tag.loadAllLazyFrames()
+
 	frames := make(map[string][]Framer)
 
 	for id, f := range tag.frames {
@@ -125,31 +193,33 @@ func (tag *Tag) DeleteAllFrames() {
 		tag.frames = make(map[string]Framer)
 	}
 	if tag.sequences == nil || len(tag.sequences) > 0 {
-		for _, s := range tag.sequences {
-			putSequence(s)
-		}
 		tag.sequences = make(map[string]*sequence)
 	}
+	tag.rawFrames = nil
+	tag.lazy = nil
 }
 
 // DeleteFrames deletes frames in tag with given id.
 func (tag *Tag) DeleteFrames(id string) {
+	tag.deleteLazyFrames(id)
 	delete(tag.frames, id)
-	if s, ok := tag.sequences[id]; ok {
-		putSequence(s)
-		delete(tag.sequences, id)
-	}
+	delete(tag.rawFrames, id)
+	delete(tag.sequences, id)
 }
 
 // Reset deletes all frames in tag and parses rd considering opts.
 func (tag *Tag) Reset(rd io.Reader, opts Options) error {
 	tag.DeleteAllFrames()
-	return tag.parse(rd, opts)
+	return tag.parse(context.Background(), rd, opts)
 }
 
 // GetFrames returns frames with corresponding id.
 // It returns nil if there is no frames with given id.
+// The returned slice is owned by caller and isn't affected by later
+// changes of tag, e.g. by DeleteFrames.
 func (tag *Tag) GetFrames(id string) []Framer {
+	tag.loadLazyFrames(id)
+
 	if f, exists := tag.frames[id]; exists {
 		return []Framer{f}
 	} else if s, exists := tag.sequences[id]; exists {
@@ -162,26 +232,23 @@ func (tag *Tag) GetFrames(id string) []Framer {
 // GetLastFrame is suitable for frames, that can be only one in whole tag.
 // For example, for text frames.
 func (tag *Tag) GetLastFrame(id string) Framer {
+	tag.loadLazyFrames(id)
+
 	// Avoid an allocation of slice in GetFrames,
-	// if there is anyway one frame.
+	// which copies all frames of sequence.
 	if f, exists := tag.frames[id]; exists {
 		return f
+	} else if s, exists := tag.sequences[id]; exists {
+		return s.lastFrame()
 	}
-
-	fs := tag.GetFrames(id)
-	if len(fs) == 0 {
-		return nil
-	}
-	return fs[len(fs)-1]
+	return nil
 }
 
 // GetTextFrame returns text frame with corresponding id.
+// If there is no such frame or it's not TextFrame (e.g. it's created
+// by custom FrameParser), it returns empty TextFrame.
 func (tag *Tag) GetTextFrame(id string) TextFrame {
-	f := tag.GetLastFrame(id)
-	if f == nil {
-		return TextFrame{}
-	}
-	tf := f.(TextFrame)
+	tf, _ := tag.GetLastFrame(id).(TextFrame)
 	return tf
 }
 
@@ -209,6 +276,8 @@ func (tag *Tag) setDefaultEncodingBasedOnVersion(version byte) {
 
 // Count returns the number of frames in tag.
 func (tag *Tag) Count() int {
+	tag.loadAllLazyFrames()
+
 	n := len(tag.frames)
 	for _, s := range tag.sequences {
 		n += s.Count()
@@ -219,7 +288,7 @@ func (tag *Tag) Count() int {
 // HasFrames checks if there is at least one frame in tag.
 // It's much faster than tag.Count() > 0.
 func (tag *Tag) HasFrames() bool {
-	return len(tag.frames) > 0 || len(tag.sequences) > 0
+	return len(tag.frames) > 0 || len(tag.sequences) > 0 || tag.hasLazyFrames()
 }
 
 func (tag *Tag) Title() string {
@@ -238,6 +307,33 @@ func (tag *Tag) SetArtist(artist string) {
 	tag.AddTextFrame(tag.CommonID("Artist"), tag.DefaultEncoding(), artist)
 }
 
+// Artists returns all values of artist frame.
+// In ID3v2.3 artists are separated by "/" according to the spec,
+// so they're split by "/", like SetArtists joins them.
+func (tag *Tag) Artists() []string {
+	return tag.textValues(tag.CommonID("Artist"))
+}
+
+// textValues returns all values of text frame with id considering
+// version of tag: in ID3v2.3 values are separated by "/".
+func (tag *Tag) textValues(id string) []string {
+	tf := tag.GetTextFrame(id)
+	if tag.version == 3 && tf.Text != "" {
+		return strings.Split(tf.Text, "/")
+	}
+	return tf.Values()
+}
+
+// SetArtists sets artist frame with multiple artists.
+// In ID3v2.3 artists are separated by "/" according to the spec.
+func (tag *Tag) SetArtists(artists ...string) {
+	if tag.version == 3 {
+		tag.SetArtist(strings.Join(artists, "/"))
+		return
+	}
+	tag.AddTextFrameValues(tag.CommonID("Artist"), tag.DefaultEncoding(), artists...)
+}
+
 func (tag *Tag) Album() string {
 	return tag.GetTextFrame(tag.CommonID("Album/Movie/Show title")).Text
 }
@@ -262,18 +358,67 @@ func (tag *Tag) SetGenre(genre string) {
 	tag.AddTextFrame(tag.CommonID("Content type"), tag.DefaultEncoding(), genre)
 }
 
+// Genres returns names of all genres in content type frame.
+// Numeric ID3v1 references are resolved. See ParseGenres for details.
+// In ID3v2.3 refinement is split by "/", so Genres returns all genres
+// set by SetGenres.
+func (tag *Tag) Genres() []string {
+	return parseGenres(tag.Genre(), tag.version)
+}
+
+// SetGenres sets content type frame with genres in the form appropriate
+// for the version of tag. Genres from ID3v1Genres are written
+// as references in ID3v2.3.
+func (tag *Tag) SetGenres(genres ...string) {
+	tag.SetGenre(formatGenres(genres, tag.version))
+}
+
+// TrackNumber returns the track number and total number of tracks
+// from track number frame. Missing or invalid numbers are returned as 0.
+func (tag *Tag) TrackNumber() (track, total int) {
+	return parsePosition(tag.GetTextFrame(tag.CommonID("Track number/Position in set")).Text)
+}
+
+// SetTrackNumber sets track number frame in format "track/total".
+// If total is not positive, only track is written.
+func (tag *Tag) SetTrackNumber(track, total int) {
+	tag.AddTextFrame(tag.CommonID("Track number/Position in set"), tag.DefaultEncoding(), formatPosition(track, total))
+}
+
+// DiscNumber returns the disc number and total number of discs
+// from part of a set frame. Missing or invalid numbers are returned as 0.
+func (tag *Tag) DiscNumber() (disc, total int) {
+	return parsePosition(tag.GetTextFrame(tag.CommonID("Part of a set")).Text)
+}
+
+// SetDiscNumber sets part of a set frame in format "disc/total".
+// If total is not positive, only disc is written.
+func (tag *Tag) SetDiscNumber(disc, total int) {
+	tag.AddTextFrame(tag.CommonID("Part of a set"), tag.DefaultEncoding(), formatPosition(disc, total))
+}
+
 // iterateOverAllFrames iterates over every single frame in tag and calls
 // f for them. It consumps no memory at all, unlike the tag.AllFrames().
 // It returns error only if f returns error.
 func (tag *Tag) iterateOverAllFrames(f func(id string, frame Framer) error) error {
+	return tag.iterateOverAllRawFrames(func(id string, frame Framer, _ []byte) error {
+		return f(id, frame)
+	})
+}
+
+// iterateOverAllRawFrames is like iterateOverAllFrames, but it also
+// passes the original data of frame, if it's kept, otherwise nil.
+func (tag *Tag) iterateOverAllRawFrames(f func(id string, frame Framer, raw []byte) error) error {
+	tag.loadAllLazyFrames()
+
 	for id, frame := range tag.frames {
-		if err := f(id, frame); err != nil {
+		if err := f(id, frame, tag.rawFrames[id]); err != nil {
 			return err
 		}
 	}
 	for id, sequence := range tag.sequences {
-		for _, frame := range sequence.Frames() {
-			if err := f(id, frame); err != nil {
+		for i, frame := range sequence.frames {
+			if err := f(id, frame, sequence.raw(i)); err != nil {
 				return err
 			}
 		}
@@ -288,9 +433,10 @@ func (tag *Tag) Size() int {
 	}
 
 	var n int
-	n += tagHeaderSize // Add the size of tag header
-	tag.iterateOverAllFrames(func(id string, f Framer) error {
-		n += frameHeaderSize + f.Size() // Add the whole frame size
+	n += tagHeaderSize            // Add the size of tag header
+	n += tag.extendedHeaderSize() // Add the size of extended header
+	tag.iterateOverAllRawFrames(func(id string, f Framer, raw []byte) error {
+		n += tag.idFrameToWrite(id, f, raw).size() // Add the whole frame size
 		return nil
 	})
 
@@ -306,10 +452,20 @@ func (tag *Tag) Version() byte {
 // If version is less than 3 or greater than 4, then this method will do nothing.
 // If tag has some frames, which are deprecated or changed in given version,
 // then to your notice you can delete, change or just stay them.
+// Use ConvertVersion to convert them to the equivalents of given version.
 func (tag *Tag) SetVersion(version byte) {
 	if version < 3 || version > 4 {
 		return
 	}
+	tag.setVersion(version)
+}
+
+// setVersion sets version to tag without converting of frames.
+func (tag *Tag) setVersion(version byte) {
+	if version != tag.version {
+		// Original frames are written only in the version they're parsed.
+		tag.deleteAllRawFrames()
+	}
 	tag.version = version
 	tag.setDefaultEncodingBasedOnVersion(version)
 }
@@ -318,85 +474,218 @@ func (tag *Tag) SetVersion(version byte) {
 // If there are no frames in tag, Save will write
 // only music part without any ID3v2 information.
 // If tag was initiliazed not with file, it returns ErrNoFile.
+// If file is not *os.File and Options.FS wasn't set, it returns ErrNoFS.
+//
+// Save is atomic: the new file is written to a unique temp file
+// in the same directory, which is synced and then replaces the original
+// file. Mode of the original file is preserved and also access and
+// modification times, if tag is opened with Options.PreserveTimes.
 func (tag *Tag) Save() error {
-	file, ok := tag.reader.(*os.File)
+	return tag.SaveContext(context.Background())
+}
+
+// SaveContext is like Save, but writing is stopped, if ctx is done.
+// Then ctx.Err() is returned, temp file is removed and the original file
+// is left untouched.
+func (tag *Tag) SaveContext(ctx context.Context) error {
+	file, ok := tag.reader.(File)
 	if !ok {
 		return ErrNoFile
 	}
+	fs, err := tag.saveFS()
+	if err != nil {
+		return err
+	}
 
-	// Get original file mode.
+	// Get original file mode and times.
 	originalFile := file
 	originalStat, err := originalFile.Stat()
 	if err != nil {
 		return err
 	}
+	name := originalFile.Name()
 
-	// Create a temp file for mp3 file, which will contain new tag.
-	name := file.Name() + "-id3v2"
-	newFile, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, originalStat.Mode())
+	// Write tag and music part in temp file.
+	var tagSize int64
+	tempName, err := writeTempFile(fs, name, originalStat.Mode(), tag.fileTimes(originalStat), func(w io.Writer) error {
+		tagSize, err = tag.writeWithMusic(ctx, w)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	// Make sure we clean up the temp file if it's still around.
-	// tempfileShouldBeRemoved created only for performance
-	// improvement to prevent calling redundant Remove syscalls if file is moved
-	// and is not need to be removed.
-	tempfileShouldBeRemoved := true
-	defer func() {
-		if tempfileShouldBeRemoved {
-			os.Remove(newFile.Name())
-		}
-	}()
+	// Close original file to allow replacing.
+	originalFile.Close()
+
+	// Replace original file with new file.
+	replaceErr := replaceFile(fs, tempName, name)
 
-	// Write tag in new file.
-	tagSize, err := tag.WriteTo(newFile)
+	// Set tag.reader to new file with original name.
+	tag.reader, err = fs.Open(name)
+	if replaceErr != nil {
+		return replaceErr
+	}
 	if err != nil {
 		return err
 	}
 
-	// Seek to a music part of original file.
-	if _, err = originalFile.Seek(tag.originalSize, os.SEEK_SET); err != nil {
+	// Set tag.originalSize to new frames size.
+	tag.originalSize = tagSize
+
+	return nil
+}
+
+// SaveTo writes tag and the music part of the original file or reader
+// to the new file with name. The original file is left untouched,
+// unless name is the original file itself, then it works like Save.
+// The tag must be initialized with a reader implementing io.ReadSeeker
+// (like *os.File), otherwise it returns ErrNoFile or ErrUnseekableReader.
+// Like Save, it returns ErrNoFS, if filesystem of the original file is
+// unknown. If tag was initialized not with File, new file is created in
+// Options.FS or in OSFS, if it's nil.
+//
+// Like Save, SaveTo is atomic and preserves mode and times
+// of the original file.
+func (tag *Tag) SaveTo(name string) error {
+	fs, err := tag.saveFS()
+	if err != nil {
 		return err
 	}
+	mode := os.FileMode(0644)
+	var times *fileTimes
+	if file, ok := tag.reader.(File); ok {
+		originalStat, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		if isSameFile(fs, file, originalStat, name) {
+			return tag.Save()
+		}
+		mode = originalStat.Mode()
+		times = tag.fileTimes(originalStat)
+	}
 
-	// Write to new file the music part.
-	buf := getByteSlice(128 * 1024)
-	defer putByteSlice(buf)
-	if _, err = io.CopyBuffer(newFile, originalFile, buf); err != nil {
+	tempName, err := writeTempFile(fs, name, mode, times, func(w io.Writer) error {
+		_, err := tag.writeWithMusic(context.Background(), w)
+		return err
+	})
+	if err != nil {
 		return err
 	}
+	return replaceFile(fs, tempName, name)
+}
 
-	// Close files to allow replacing.
-	newFile.Close()
-	originalFile.Close()
+// saveFS returns the filesystem, which Save and SaveTo write files to.
+// OSFS is used only if tag.fs is nil and tag is initialized not with File
+// or with *os.File. Other files can't be saved without tag.fs, because
+// their names can mean other files in OS.
+func (tag *Tag) saveFS() (FS, error) {
+	if tag.fs != nil {
+		return tag.fs, nil
+	}
+	if file, ok := tag.reader.(File); ok {
+		if _, ok := file.(*os.File); !ok {
+			return nil, ErrNoFS
+		}
+	}
+	return OSFS{}, nil
+}
 
-	// Replace original file with new file.
-	if err = os.Rename(newFile.Name(), originalFile.Name()); err != nil {
-		return err
+// isSameFile checks if file with stat is the file with name in fs.
+func isSameFile(fs FS, file File, stat os.FileInfo, name string) bool {
+	nameStat, err := fs.Stat(name)
+	if err != nil {
+		return false
+	}
+	if os.SameFile(nameStat, stat) {
+		return true
 	}
-	tempfileShouldBeRemoved = false
+	// os.SameFile works only with os.FileInfo returned by package os.
+	if _, ok := fs.(OSFS); ok {
+		return false
+	}
+	return filepath.Clean(file.Name()) == filepath.Clean(name)
+}
 
-	// Set tag.reader to new file with original name.
-	tag.reader, err = os.Open(originalFile.Name())
+// SaveToWriter writes tag and the music part of the original file or
+// reader to w. The original file is left untouched.
+// The tag must be initialized with a reader implementing io.ReadSeeker
+// (like *os.File), otherwise it returns ErrNoFile or ErrUnseekableReader.
+func (tag *Tag) SaveToWriter(w io.Writer) error {
+	if w == nil {
+		return errors.New("w is nil")
+	}
+	_, err := tag.writeWithMusic(context.Background(), w)
+	return err
+}
+
+// writeWithMusic writes tag and then the music part of tag.reader, which
+// begins after the original tag, to w. It returns the size of written tag.
+// If ctx is done, writing is stopped and ctx.Err() is returned.
+func (tag *Tag) writeWithMusic(ctx context.Context, w io.Writer) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if tag.reader == nil {
+		return 0, ErrNoFile
+	}
+	seeker, ok := tag.reader.(io.ReadSeeker)
+	if !ok {
+		return 0, ErrUnseekableReader
+	}
+
+	// Write tag.
+	tagSize, err := tag.WriteTo(w)
 	if err != nil {
-		return err
+		return tagSize, err
 	}
 
-	// Set tag.originalSize to new frames size.
-	tag.originalSize = tagSize
+	// Seek to a music part of original reader.
+	if _, err := seeker.Seek(tag.originalSize, io.SeekStart); err != nil {
+		return tagSize, err
+	}
 
-	return nil
+	// Write the music part.
+	buf := getByteSlice(128 * 1024)
+	defer putByteSlice(buf)
+	_, err = io.CopyBuffer(w, contextReader{ctx: ctx, rd: seeker}, buf)
+	return tagSize, err
+}
+
+// contextReader reads from rd until ctx is done, then it returns ctx.Err().
+type contextReader struct {
+	ctx context.Context
+	rd  io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.rd.Read(p)
 }
 
 // WriteTo writes whole tag in w if there is at least one frame.
 // It returns the number of bytes written and error during the write.
 // It returns nil as error if the write was successful.
+// If tag is parsed with Options.Lazy and some frames can't be loaded,
+// it returns the error of loading and writes nothing.
+// If tag violates its restrictions, it returns error wrapping
+// ErrRestrictionViolation and writes nothing (see SetRestrictions).
 func (tag *Tag) WriteTo(w io.Writer) (n int64, err error) {
 	if w == nil {
 		return 0, errors.New("w is nil")
 	}
 
+	if err := tag.loadAllLazyFrames(); err != nil {
+		return 0, err
+	}
+
+	if err := tag.checkRestrictions(); err != nil {
+		return 0, err
+	}
+
 	// Count size of frames.
 	framesSize := tag.Size() - tagHeaderSize
 	if framesSize <= 0 {
@@ -406,14 +695,38 @@ func (tag *Tag) WriteTo(w io.Writer) (n int64, err error) {
 	// Write tag header.
 	bw := getBufWriter(w)
 	defer putBufWriter(bw)
-	writeTagHeader(bw, uint(framesSize), tag.version)
+	var flags byte
+	if tag.extendedHeaderSize() > 0 {
+		flags |= flagExtendedHeader
+	}
+	writeTagHeaderFlags(bw, uint(framesSize), tag.version, flags)
 
-	// Write frames.
-	synchSafe := tag.Version() == 4
-	err = tag.iterateOverAllFrames(func(id string, f Framer) error {
-		return writeFrame(bw, id, f, synchSafe)
+	// Collect frames, so they're written in the same order,
+	// if CRC-32 of them is computed before.
+	var frames []idFrame
+	tag.iterateOverAllRawFrames(func(id string, f Framer, raw []byte) error {
+		frames = append(frames, tag.idFrameToWrite(id, f, raw))
+		return nil
 	})
-	if err != nil {
+
+	var crc uint32
+	if tag.crc {
+		hash := crc32.NewIEEE()
+		hw := getBufWriter(hash)
+		err := tag.writeFrames(hw, frames)
+		if err == nil {
+			err = hw.Flush()
+		}
+		putBufWriter(hw)
+		if err != nil {
+			return 0, err
+		}
+		crc = hash.Sum32()
+	}
+	tag.writeExtendedHeader(bw, crc)
+
+	// Write frames.
+	if err := tag.writeFrames(bw, frames); err != nil {
 		bw.Flush()
 		return int64(bw.Written()), err
 	}
@@ -421,11 +734,38 @@ func (tag *Tag) WriteTo(w io.Writer) (n int64, err error) {
 	return int64(bw.Written()), bw.Flush()
 }
 
+// idFrame is frame with its ID and original data, if it's kept.
+type idFrame struct {
+	id    string
+	frame Framer
+	raw   []byte
+}
+
+func (tag *Tag) writeFrames(bw *bufWriter, frames []idFrame) error {
+	synchSafe := tag.Version() == 4
+	for _, f := range frames {
+		if f.raw != nil {
+			if _, err := bw.Write(f.raw); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeFrame(bw, f.id, f.frame, synchSafe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeTagHeader(bw *bufWriter, framesSize uint, version byte) {
+	writeTagHeaderFlags(bw, framesSize, version, 0)
+}
+
+func writeTagHeaderFlags(bw *bufWriter, framesSize uint, version byte, flags byte) {
 	bw.Write(id3Identifier)
 	bw.WriteByte(version)
 	bw.WriteByte(0) // Revision
-	bw.WriteByte(0) // Flags
+	bw.WriteByte(flags)
 	bw.WriteBytesSize(framesSize, true)
 }
 
@@ -444,7 +784,7 @@ func writeFrameHeader(bw *bufWriter, id string, frameSize uint, synchSafe bool)
 // Close closes tag's file, if tag was opened with a file.
 // If tag was initiliazed not with file, it returns ErrNoFile.
 func (tag *Tag) Close() error {
-	file, ok := tag.reader.(*os.File)
+	file, ok := tag.reader.(File)
 	if !ok {
 		return ErrNoFile
 	}
@@ -546,6 +546,25 @@ func TestEncodedText(t *testing.T) {
 	}
 }
 
+func TestGetLastFrameOfSequence(t *testing.T) {
+	tag := NewEmptyTag()
+	tag.AddCommentFrame(engComm)
+	tag.AddCommentFrame(gerComm)
+
+	cf := tag.GetLastFrame(tag.CommonID("Comments")).(CommentFrame)
+	if err := compareCommentFrames(cf, gerComm); err != nil {
+		t.Error(err)
+	}
+
+	// Frames of sequence must not be copied.
+	allocs := testing.AllocsPerRun(100, func() {
+		tag.GetLastFrame("COMM")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
 func TestWriteToN(t *testing.T) {
 	tag := NewEmptyTag()
 
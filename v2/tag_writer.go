@@ -0,0 +1,182 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"errors"
+	"io"
+)
+
+// SizeUnknown can be passed to NewTagWriter as size of frames,
+// if it's not known up front.
+const SizeUnknown = -1
+
+// ErrTagWriterClosed is returned by methods of TagWriter after Close.
+var ErrTagWriterClosed = errors.New("tag writer is closed")
+
+// TagWriter writes ID3v2 tag frame by frame without building a Tag,
+// so bodies of frames can be streamed from readers (e.g. huge pictures
+// from network). Close must be called after all frames are written.
+type TagWriter struct {
+	w          io.Writer
+	bw         *bufWriter
+	version    byte
+	framesSize int64
+	written    int64
+
+	// start is the position of tag in w, if size of frames is unknown.
+	start int64
+
+	closed bool
+}
+
+// FrameSize returns the size of frame f including frame header, how it
+// will be written by TagWriter. It's useful to count the size of frames
+// for NewTagWriter.
+func FrameSize(f Framer) int64 {
+	return frameHeaderSize + int64(f.Size())
+}
+
+// NewTagWriter writes tag header with given version to w and returns
+// TagWriter, which writes frames of this tag to w.
+//
+// framesSize is the size of all frames, which will be written, including
+// their headers (see FrameSize). If less bytes are written, Close fills
+// the rest of tag with padding. If framesSize is SizeUnknown, w must
+// implement io.WriteSeeker, so Close can write the real size to tag header.
+func NewTagWriter(w io.Writer, version byte, framesSize int64) (*TagWriter, error) {
+	if w == nil {
+		return nil, errors.New("w is nil")
+	}
+	if version < 3 || version > 4 {
+		return nil, ErrUnsupportedVersion
+	}
+
+	tw := &TagWriter{w: w, bw: newBufWriter(w), version: version, framesSize: framesSize}
+
+	size := framesSize
+	if framesSize == SizeUnknown {
+		seeker, ok := w.(io.Seeker)
+		if !ok {
+			return nil, errors.New("w must implement io.Seeker, if size of frames is unknown")
+		}
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		tw.start = start
+		size = 0
+	} else if framesSize < 0 || framesSize > synchSafeMaxSize {
+		return nil, ErrSizeOverflow
+	}
+
+	writeTagHeader(tw.bw, uint(size), version)
+	return tw, tw.bw.Flush()
+}
+
+// WriteFrame writes frame f with id.
+// If the frame goes over the size of frames passed to NewTagWriter,
+// it returns ErrBodyOverflow.
+func (tw *TagWriter) WriteFrame(id string, f Framer) error {
+	bodySize := int64(f.Size())
+	if err := tw.writeFrameHeader(id, bodySize); err != nil {
+		return err
+	}
+	if _, err := f.WriteTo(tw.bw); err != nil {
+		return err
+	}
+	return tw.bw.Flush()
+}
+
+// WriteFrameFrom writes frame with id, which body of size bytes
+// is read from body. The body must be already encoded in accordance
+// with ID3v2 specification. If body has less bytes than size,
+// it returns io.ErrUnexpectedEOF.
+func (tw *TagWriter) WriteFrameFrom(id string, size int64, body io.Reader) error {
+	if err := tw.writeFrameHeader(id, size); err != nil {
+		return err
+	}
+	return tw.copyBody(body, size)
+}
+
+// WritePictureFrameFrom writes picture frame pf, which picture of size bytes
+// is read from picture. pf.Picture is ignored.
+// The size of this frame is FrameSize(pf) + size, if pf.Picture is nil.
+func (tw *TagWriter) WritePictureFrameFrom(pf PictureFrame, size int64, picture io.Reader) error {
+	pf.Picture = nil
+	if err := tw.writeFrameHeader("APIC", int64(pf.Size())+size); err != nil {
+		return err
+	}
+	if _, err := pf.WriteTo(tw.bw); err != nil {
+		return err
+	}
+	return tw.copyBody(picture, size)
+}
+
+func (tw *TagWriter) writeFrameHeader(id string, bodySize int64) error {
+	if tw.closed {
+		return ErrTagWriterClosed
+	}
+	if len(id) != 4 {
+		return errors.New("frame id must consist of four characters")
+	}
+
+	frameSize := frameHeaderSize + bodySize
+	if tw.framesSize != SizeUnknown && tw.written+frameSize > tw.framesSize {
+		return ErrBodyOverflow
+	}
+
+	writeFrameHeader(tw.bw, id, uint(bodySize), tw.version == 4)
+	if err := tw.bw.Flush(); err != nil {
+		return err
+	}
+	tw.written += frameSize
+	return nil
+}
+
+func (tw *TagWriter) copyBody(body io.Reader, size int64) error {
+	buf := getByteSlice(32 * 1024)
+	defer putByteSlice(buf)
+
+	n, err := io.CopyBuffer(tw.bw, io.LimitReader(body, size), buf)
+	if err != nil {
+		return err
+	}
+	if n < size {
+		return io.ErrUnexpectedEOF
+	}
+	return tw.bw.Flush()
+}
+
+// Close fills the rest of tag with padding, if less frames are written than
+// it was passed to NewTagWriter, or writes the size of frames to tag header,
+// if it was unknown. It doesn't close the underlying writer.
+// After Close all methods of TagWriter return ErrTagWriterClosed.
+func (tw *TagWriter) Close() error {
+	if tw.closed {
+		return ErrTagWriterClosed
+	}
+	tw.closed = true
+
+	if tw.framesSize != SizeUnknown {
+		for i := tw.written; i < tw.framesSize; i++ {
+			tw.bw.WriteByte(0)
+		}
+		tw.written = tw.framesSize
+		return tw.bw.Flush()
+	}
+
+	// Write the size of frames to tag header and return back to the end of tag.
+	seeker := tw.w.(io.Seeker)
+	if _, err := seeker.Seek(tw.start+tagHeaderSize-id3SizeLen, io.SeekStart); err != nil {
+		return err
+	}
+	tw.bw.WriteBytesSize(uint(tw.written), true)
+	if err := tw.bw.Flush(); err != nil {
+		return err
+	}
+	_, err := seeker.Seek(tw.start+tagHeaderSize+tw.written, io.SeekStart)
+	return err
+}
@@ -0,0 +1,163 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestTagWriter(t *testing.T) {
+	t.Parallel()
+
+	title := TextFrame{Encoding: EncodingUTF8, Text: "Title"}
+	pictureHeader := frontCover
+	pictureHeader.Picture = nil
+	pictureSize := int64(len(frontCover.Picture))
+	framesSize := FrameSize(title) + FrameSize(pictureHeader) + pictureSize + FrameSize(unknownFrame)
+
+	// Add some padding.
+	framesSize += 100
+
+	buf := new(bytes.Buffer)
+	tw, err := NewTagWriter(buf, 4, framesSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteFrame("TIT2", title); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WritePictureFrameFrom(frontCover, pictureSize, bytes.NewReader(frontCover.Picture)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteFrameFrom(unknownFrameID, int64(len(unknownFrame.Body)), bytes.NewReader(unknownFrame.Body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if int64(buf.Len()) != tagHeaderSize+framesSize {
+		t.Errorf("Expected %v written bytes, got %v", tagHeaderSize+framesSize, buf.Len())
+	}
+
+	tag, err := ParseReader(buf, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tag.Title())
+	}
+	testPictureFrame(t, tag, frontCover)
+	testUnknownFrames(t, tag)
+}
+
+func testPictureFrame(t *testing.T, tag *Tag, expected PictureFrame) {
+	pf, ok := tag.GetLastFrame("APIC").(PictureFrame)
+	if !ok {
+		t.Fatal("Couldn't assert picture frame")
+	}
+	if err := comparePictureFrames(pf, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTagWriterOverflow(t *testing.T) {
+	t.Parallel()
+
+	title := TextFrame{Encoding: EncodingUTF8, Text: "Title"}
+
+	tw, err := NewTagWriter(ioutil.Discard, 4, FrameSize(title)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteFrame("TIT2", title); err != ErrBodyOverflow {
+		t.Errorf("Expected %v, got %v", ErrBodyOverflow, err)
+	}
+}
+
+func TestTagWriterShortBody(t *testing.T) {
+	t.Parallel()
+
+	tw, err := NewTagWriter(ioutil.Discard, 3, SizeUnknown)
+	if err == nil {
+		t.Error("Expected error for unknown size without io.Seeker")
+	}
+
+	tw, err = NewTagWriter(ioutil.Discard, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteFrameFrom("WXXX", 10, bytes.NewReader([]byte("short"))); err == nil {
+		t.Error("Expected error for body shorter than size")
+	}
+}
+
+func TestTagWriterUnknownSize(t *testing.T) {
+	t.Parallel()
+
+	file, err := ioutil.TempFile("", "id3v2-tag-writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	tw, err := NewTagWriter(file, 3, SizeUnknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteFrame("TIT2", TextFrame{Encoding: EncodingISO, Text: "Title"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WritePictureFrameFrom(backCover, int64(len(backCover.Picture)), bytes.NewReader(backCover.Picture)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Write some music after the tag.
+	if _, err := file.Write([]byte{255, 251, 80, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	tag, err := ParseReader(file, parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Version() != 3 || tag.Title() != "Title" {
+		t.Errorf("Expected ID3v2.3 tag with title %q, got ID3v2.%v with title %q", "Title", tag.Version(), tag.Title())
+	}
+	testPictureFrame(t, tag, backCover)
+}
+
+func TestTagWriterClosed(t *testing.T) {
+	t.Parallel()
+
+	tw, err := NewTagWriter(new(bytes.Buffer), 4, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tw.WriteFrame("TIT2", TextFrame{Encoding: EncodingISO, Text: "Title"}); err != ErrTagWriterClosed {
+		t.Errorf("Expected %v by writing frame, got %v", ErrTagWriterClosed, err)
+	}
+	if err := tw.WriteFrameFrom("TIT2", 1, bytes.NewReader([]byte{0})); err != ErrTagWriterClosed {
+		t.Errorf("Expected %v by writing frame from reader, got %v", ErrTagWriterClosed, err)
+	}
+	if err := tw.Close(); err != ErrTagWriterClosed {
+		t.Errorf("Expected %v by closing twice, got %v", ErrTagWriterClosed, err)
+	}
+}
@@ -4,16 +4,46 @@
 
 package id3v2
 
-import "io"
+import (
+	"io"
+	"strings"
+)
+
+// textValuesSeparator separates multiple values in Text of TextFrame.
+// It's written as termination bytes of frame's encoding.
+const textValuesSeparator = "\x00"
 
 // TextFrame is used to work with all text frames
 // (all T*** frames like TIT2 (title), TALB (album) and so on).
+//
+// ID3v2.4 allows text frames to contain multiple values (e.g. several
+// artists in TPE1). They are stored in Text separated by null character.
+// Use Values to get them as slice.
 type TextFrame struct {
 	Encoding Encoding
 	Text     string
 }
 
+// Values returns all values of text frame.
+// It returns nil if Text is blank.
+// Values separated by "/" in ID3v2.3 are not split, because "/" may be
+// a part of value. Use Tag.Artists to get them split by version of tag.
+func (tf TextFrame) Values() []string {
+	if tf.Text == "" {
+		return nil
+	}
+	return strings.Split(tf.Text, textValuesSeparator)
+}
+
+func (tf TextFrame) hasMultipleValues() bool {
+	return strings.Contains(tf.Text, textValuesSeparator)
+}
+
 func (tf TextFrame) Size() int {
+	if tf.hasMultipleValues() {
+		values := tf.Values()
+		return 1 + encodedValuesSize(values, tf.Encoding) + len(tf.Encoding.TerminationBytes)
+	}
 	return 1 + encodedSize(tf.Text, tf.Encoding) + len(tf.Encoding.TerminationBytes)
 }
 
@@ -24,7 +54,11 @@ func (tf TextFrame) UniqueIdentifier() string {
 func (tf TextFrame) WriteTo(w io.Writer) (int64, error) {
 	return useBufWriter(w, func(bw *bufWriter) {
 		bw.WriteByte(tf.Encoding.Key)
-		bw.EncodeAndWriteText(tf.Text, tf.Encoding)
+		if tf.hasMultipleValues() {
+			bw.EncodeAndWriteTextValues(tf.Values(), tf.Encoding)
+		} else {
+			bw.EncodeAndWriteText(tf.Text, tf.Encoding)
+		}
 
 		// https://github.com/bogem/id3v2/pull/52
 		// https://github.com/bogem/id3v2/pull/33
@@ -45,9 +79,15 @@ func parseTextFrame(br *bufReader) (Framer, error) {
 		return nil, err
 	}
 
+	encodedValues := splitEncodedValues(buf.Bytes(), encoding)
+	values := make([]string, 0, len(encodedValues))
+	for _, encodedValue := range encodedValues {
+		values = append(values, decodeText(encodedValue, encoding))
+	}
+
 	tf := TextFrame{
 		Encoding: encoding,
-		Text:     decodeText(buf.Bytes(), encoding),
+		Text:     strings.Join(values, textValuesSeparator),
 	}
 
 	return tf, nil
@@ -0,0 +1,76 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestTextFrameMultipleValues(t *testing.T) {
+	t.Parallel()
+
+	values := []string{"Héllö", "Wörld", "Foo"}
+
+	for _, encoding := range []Encoding{EncodingISO, EncodingUTF16, EncodingUTF16BE, EncodingUTF8} {
+		tag := NewEmptyTag()
+		tag.AddTextFrameValues("TPE1", encoding, values...)
+
+		buf := new(bytes.Buffer)
+		n, err := tag.WriteTo(buf)
+		if err != nil {
+			t.Fatalf("Error by writing tag with %v: %v", encoding, err)
+		}
+		if n != int64(tag.Size()) {
+			t.Errorf("Expected WriteTo n==%v with %v, got %v", tag.Size(), encoding, n)
+		}
+
+		parsed, err := ParseReader(buf, parseOpts)
+		if err != nil {
+			t.Fatalf("Error by parsing tag with %v: %v", encoding, err)
+		}
+		if got := parsed.Artists(); !reflect.DeepEqual(got, values) {
+			t.Errorf("Expected artists %q with %v, got %q", values, encoding, got)
+		}
+	}
+}
+
+func TestTextFrameSingleValue(t *testing.T) {
+	t.Parallel()
+
+	for _, encoding := range []Encoding{EncodingISO, EncodingUTF16, EncodingUTF16BE, EncodingUTF8} {
+		tf := TextFrame{Encoding: encoding, Text: "Title"}
+
+		buf := new(bytes.Buffer)
+		if _, err := tf.WriteTo(buf); err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, err := parseTextFrame(newBufReader(buf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := parsed.(TextFrame).Values()
+		if !reflect.DeepEqual(got, []string{"Title"}) {
+			t.Errorf("Expected only %q with %v, got %q", "Title", encoding, got)
+		}
+	}
+}
+
+func TestSetArtistsV23(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetVersion(3)
+	tag.SetArtists("Foo", "Bar")
+
+	if tag.Artist() != "Foo/Bar" {
+		t.Errorf("Expected artist %q, got %q", "Foo/Bar", tag.Artist())
+	}
+	if artists := tag.Artists(); !reflect.DeepEqual(artists, []string{"Foo", "Bar"}) {
+		t.Errorf("Expected artists %q, got %q", []string{"Foo", "Bar"}, artists)
+	}
+}
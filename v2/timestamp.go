@@ -0,0 +1,204 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTimestamp = errors.New("invalid format of timestamp")
+
+// ErrUnsupportedTimeFrame is returned by SetTimestamp, if time frame
+// is not defined in ID3v2.3 and has no equivalent in it.
+var ErrUnsupportedTimeFrame = errors.New("time frame is not defined in ID3v2.3")
+
+// TimestampPrecision is the precision of Timestamp.
+type TimestampPrecision byte
+
+// Available precisions of Timestamp.
+const (
+	PrecisionYear TimestampPrecision = iota + 1
+	PrecisionMonth
+	PrecisionDay
+	PrecisionHour
+	PrecisionMinute
+	PrecisionSecond
+)
+
+// timestampLayouts are layouts of timestamp for every precision.
+var timestampLayouts = map[TimestampPrecision]string{
+	PrecisionYear:   "2006",
+	PrecisionMonth:  "2006-01",
+	PrecisionDay:    "2006-01-02",
+	PrecisionHour:   "2006-01-02T15",
+	PrecisionMinute: "2006-01-02T15:04",
+	PrecisionSecond: "2006-01-02T15:04:05",
+}
+
+// Timestamp is used to work with time frames of ID3v2.4 (TDRC, TDOR, TDRL,
+// TDEN and TDTG). Timestamps are written in format yyyy[-MM[-dd[THH[:mm[:ss]]]]],
+// so Precision tells which fields of Time are actually set.
+type Timestamp struct {
+	Time      time.Time
+	Precision TimestampPrecision
+}
+
+// ParseTimestamp parses s in format yyyy[-MM[-dd[THH[:mm[:ss]]]]].
+// Time of returned timestamp is in UTC.
+// If s has other format, it returns ErrInvalidTimestamp.
+func ParseTimestamp(s string) (Timestamp, error) {
+	s = strings.TrimSpace(s)
+	// Some taggers separate date and time with space.
+	if len(s) > 10 && s[10] == ' ' {
+		s = s[:10] + "T" + s[11:]
+	}
+
+	for precision, layout := range timestampLayouts {
+		if len(s) != len(layout) {
+			continue
+		}
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return Timestamp{}, ErrInvalidTimestamp
+		}
+		return Timestamp{Time: t, Precision: precision}, nil
+	}
+
+	return Timestamp{}, ErrInvalidTimestamp
+}
+
+// IsZero reports whether ts is not set.
+func (ts Timestamp) IsZero() bool {
+	return ts.Precision == 0
+}
+
+// String returns ts in format yyyy[-MM[-dd[THH[:mm[:ss]]]]] considering
+// its precision. It returns "" if ts is zero.
+func (ts Timestamp) String() string {
+	layout, ok := timestampLayouts[ts.Precision]
+	if !ok {
+		return ""
+	}
+	return ts.Time.Format(layout)
+}
+
+// Timestamp returns timestamp of ID3v2.4 time frame with given id
+// (e.g. "TDRC"). In ID3v2.3 tag it's converted from the frames
+// of ID3v2.3: TDRC from TYER, TDAT and TIME (or TRDA if there is no TYER),
+// TDOR from TORY. If there is no such frame, it returns zero timestamp.
+// TRDA is free-form text, so it's used only if it's a valid timestamp.
+func (tag *Tag) Timestamp(id string) (Timestamp, error) {
+	if tag.version == 3 {
+		switch id {
+		case "TDRC":
+			year := tag.GetTextFrame("TYER").Text
+			if year == "" {
+				if ts, err := parseTimestampText(tag.GetTextFrame("TRDA").Text); err == nil {
+					return ts, nil
+				}
+				return Timestamp{}, nil
+			}
+			return timestampFromV23(year, tag.GetTextFrame("TDAT").Text, tag.GetTextFrame("TIME").Text)
+		case "TDOR":
+			return parseTimestampText(tag.GetTextFrame("TORY").Text)
+		}
+	}
+
+	return parseTimestampText(tag.GetTextFrame(id).Text)
+}
+
+// SetTimestamp sets ID3v2.4 time frame with given id (e.g. "TDRC") to ts.
+// In ID3v2.3 tag TDRC is written to TYER, TDAT and TIME
+// and TDOR to TORY considering precision of ts. TIME has no hour
+// precision, so timestamp with hour precision is written without TIME.
+// TDRL, TDEN and TDTG have no equivalents in ID3v2.3, so for them
+// it returns ErrUnsupportedTimeFrame in ID3v2.3 tag.
+func (tag *Tag) SetTimestamp(id string, ts Timestamp) error {
+	if tag.version == 3 {
+		switch id {
+		case "TDRL", "TDEN", "TDTG":
+			return ErrUnsupportedTimeFrame
+		case "TDRC":
+			year, date, tm := timestampToV23(ts)
+			tag.setOrDeleteTextFrame("TYER", year)
+			tag.setOrDeleteTextFrame("TDAT", date)
+			tag.setOrDeleteTextFrame("TIME", tm)
+			return nil
+		case "TDOR":
+			year, _, _ := timestampToV23(ts)
+			tag.setOrDeleteTextFrame("TORY", year)
+			return nil
+		}
+	}
+
+	tag.setOrDeleteTextFrame(id, ts.String())
+	return nil
+}
+
+// setOrDeleteTextFrame adds text frame with default encoding to tag
+// or deletes frames with id if text is blank.
+func (tag *Tag) setOrDeleteTextFrame(id, text string) {
+	if text == "" {
+		tag.DeleteFrames(id)
+		return
+	}
+	tag.AddTextFrame(id, tag.DefaultEncoding(), text)
+}
+
+// parseTimestampText parses text of time frame.
+// It returns zero timestamp if text is blank.
+func parseTimestampText(text string) (Timestamp, error) {
+	if strings.TrimSpace(text) == "" {
+		return Timestamp{}, nil
+	}
+	return ParseTimestamp(text)
+}
+
+// timestampFromV23 converts texts of ID3v2.3 frames TYER ("yyyy"),
+// TDAT ("DDMM") and TIME ("HHMM") to timestamp.
+// Invalid TDAT and TIME are ignored.
+func timestampFromV23(year, date, tm string) (Timestamp, error) {
+	ts, err := ParseTimestamp(year)
+	if err != nil || ts.Precision != PrecisionYear {
+		return Timestamp{}, ErrInvalidTimestamp
+	}
+
+	d, err := time.Parse("0201", strings.TrimSpace(date))
+	if err != nil {
+		return ts, nil
+	}
+	ts.Time = time.Date(ts.Time.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	ts.Precision = PrecisionDay
+
+	t, err := time.Parse("1504", strings.TrimSpace(tm))
+	if err != nil {
+		return ts, nil
+	}
+	ts.Time = ts.Time.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+	ts.Precision = PrecisionMinute
+
+	return ts, nil
+}
+
+// timestampToV23 converts ts to texts of ID3v2.3 frames TYER ("yyyy"),
+// TDAT ("DDMM") and TIME ("HHMM"). Texts, which can't be represented
+// with precision of ts, are blank. TIME is blank also for hour precision,
+// because it would add minutes, which ts doesn't have.
+func timestampToV23(ts Timestamp) (year, date, tm string) {
+	if ts.IsZero() {
+		return "", "", ""
+	}
+
+	year = ts.Time.Format("2006")
+	if ts.Precision >= PrecisionDay {
+		date = ts.Time.Format("0201")
+	}
+	if ts.Precision >= PrecisionMinute {
+		tm = ts.Time.Format("1504")
+	}
+	return year, date, tm
+}
@@ -0,0 +1,158 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text      string
+		expected  time.Time
+		precision TimestampPrecision
+	}{
+		{"2016", time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), PrecisionYear},
+		{"2016-05", time.Date(2016, 5, 1, 0, 0, 0, 0, time.UTC), PrecisionMonth},
+		{"2016-05-17", time.Date(2016, 5, 17, 0, 0, 0, 0, time.UTC), PrecisionDay},
+		{"2016-05-17T13", time.Date(2016, 5, 17, 13, 0, 0, 0, time.UTC), PrecisionHour},
+		{"2016-05-17T13:37", time.Date(2016, 5, 17, 13, 37, 0, 0, time.UTC), PrecisionMinute},
+		{"2016-05-17T13:37:42", time.Date(2016, 5, 17, 13, 37, 42, 0, time.UTC), PrecisionSecond},
+		{" 2016-05-17 13:37:42 ", time.Date(2016, 5, 17, 13, 37, 42, 0, time.UTC), PrecisionSecond},
+	}
+
+	for _, tc := range testCases {
+		ts, err := ParseTimestamp(tc.text)
+		if err != nil {
+			t.Errorf("Error by parsing %q: %v", tc.text, err)
+			continue
+		}
+		if !ts.Time.Equal(tc.expected) || ts.Precision != tc.precision {
+			t.Errorf("Expected %v with precision %v, got %v with precision %v", tc.expected, tc.precision, ts.Time, ts.Precision)
+		}
+	}
+
+	for _, text := range []string{"", "16", "2016-5", "2016-13-01", "2016/05/17"} {
+		if _, err := ParseTimestamp(text); err != ErrInvalidTimestamp {
+			t.Errorf("Expected ErrInvalidTimestamp by parsing %q, got %v", text, err)
+		}
+	}
+}
+
+func TestTimestampString(t *testing.T) {
+	t.Parallel()
+
+	tm := time.Date(2016, 5, 17, 13, 37, 42, 0, time.UTC)
+	if s := (Timestamp{Time: tm, Precision: PrecisionDay}).String(); s != "2016-05-17" {
+		t.Errorf("Expected %q, got %q", "2016-05-17", s)
+	}
+	if s := (Timestamp{}).String(); s != "" {
+		t.Errorf("Expected blank string, got %q", s)
+	}
+}
+
+func TestTimestampV23(t *testing.T) {
+	t.Parallel()
+
+	ts := Timestamp{Time: time.Date(2016, 5, 17, 13, 37, 0, 0, time.UTC), Precision: PrecisionMinute}
+
+	tag := NewEmptyTag()
+	tag.SetVersion(3)
+	tag.SetTimestamp("TDRC", ts)
+
+	if tag.GetTextFrame("TYER").Text != "2016" || tag.GetTextFrame("TDAT").Text != "1705" || tag.GetTextFrame("TIME").Text != "1337" {
+		t.Errorf("Expected TYER, TDAT and TIME %q, %q and %q, got %q, %q and %q", "2016", "1705", "1337",
+			tag.GetTextFrame("TYER").Text, tag.GetTextFrame("TDAT").Text, tag.GetTextFrame("TIME").Text)
+	}
+
+	got, err := tag.Timestamp("TDRC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Time.Equal(ts.Time) || got.Precision != ts.Precision {
+		t.Errorf("Expected %v, got %v", ts, got)
+	}
+
+	tag.SetTimestamp("TDRC", Timestamp{Time: ts.Time, Precision: PrecisionHour})
+	if tag.GetTextFrame("TDAT").Text != "1705" || tag.GetLastFrame("TIME") != nil {
+		t.Errorf("Expected TDAT %q and no TIME for timestamp with hour precision, got %q and %q",
+			"1705", tag.GetTextFrame("TDAT").Text, tag.GetTextFrame("TIME").Text)
+	}
+	if got, err := tag.Timestamp("TDRC"); err != nil || got.Precision != PrecisionDay {
+		t.Errorf("Expected timestamp with day precision, got %v (error %v)", got, err)
+	}
+
+	tag.SetTimestamp("TDRC", Timestamp{Time: ts.Time, Precision: PrecisionYear})
+	if tag.GetLastFrame("TDAT") != nil || tag.GetLastFrame("TIME") != nil {
+		t.Error("TDAT and TIME should be deleted for timestamp with year precision")
+	}
+}
+
+func TestTimestampV24(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTimestamp("TDOR", Timestamp{Time: time.Date(1999, 12, 1, 0, 0, 0, 0, time.UTC), Precision: PrecisionMonth})
+
+	if tag.GetTextFrame("TDOR").Text != "1999-12" {
+		t.Errorf("Expected TDOR %q, got %q", "1999-12", tag.GetTextFrame("TDOR").Text)
+	}
+
+	ts, err := tag.Timestamp("TDRC")
+	if err != nil || !ts.IsZero() {
+		t.Errorf("Expected zero timestamp without error, got %v and %v", ts, err)
+	}
+}
+
+func TestSetTimestampUnsupportedInV23(t *testing.T) {
+	t.Parallel()
+
+	ts := Timestamp{Time: time.Date(2016, 5, 17, 0, 0, 0, 0, time.UTC), Precision: PrecisionDay}
+
+	for _, id := range []string{"TDRL", "TDEN", "TDTG"} {
+		tag := NewEmptyTag()
+		tag.SetVersion(3)
+		if err := tag.SetTimestamp(id, ts); err != ErrUnsupportedTimeFrame {
+			t.Errorf("%v: expected %v, got %v", id, ErrUnsupportedTimeFrame, err)
+		}
+		if tag.Count() != 0 {
+			t.Errorf("%v: expected no frames, got %v", id, tag.Count())
+		}
+
+		tag.SetVersion(4)
+		if err := tag.SetTimestamp(id, ts); err != nil {
+			t.Errorf("%v: expected no error in ID3v2.4, got %v", id, err)
+		}
+	}
+}
+
+func TestTimestampV23RecordingDates(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		trda     string
+		expected Timestamp
+	}{
+		{"4th-7th June", Timestamp{}},
+		{"2016-05-17", Timestamp{Time: time.Date(2016, 5, 17, 0, 0, 0, 0, time.UTC), Precision: PrecisionDay}},
+	}
+
+	for _, tc := range testCases {
+		tag := NewEmptyTag()
+		tag.SetVersion(3)
+		tag.AddTextFrame("TRDA", EncodingISO, tc.trda)
+
+		ts, err := tag.Timestamp("TDRC")
+		if err != nil {
+			t.Errorf("TRDA %q: expected no error, got %v", tc.trda, err)
+		}
+		if !ts.Time.Equal(tc.expected.Time) || ts.Precision != tc.expected.Precision {
+			t.Errorf("TRDA %q: expected %v, got %v", tc.trda, tc.expected, ts)
+		}
+	}
+}
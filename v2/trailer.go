@@ -0,0 +1,217 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	tagFooterSize = 10
+
+	id3v1Size         = 128
+	id3v1EnhancedSize = 227
+	apeFooterSize     = 32
+
+	// apeFlagHeader is the flag of APE tag footer, which means,
+	// that tag has header.
+	apeFlagHeader = 1 << 31
+)
+
+var (
+	id3v1Identifier         = []byte("TAG")
+	id3v1EnhancedIdentifier = []byte("TAG+")
+	apeIdentifier           = []byte("APETAGEX")
+	footerIdentifier        = []byte("3DI")
+)
+
+// TrailerKind is the kind of tag found at the end of reader.
+type TrailerKind int
+
+const (
+	// TrailerID3v1 is ID3v1 tag, possibly with enhanced tag before it.
+	TrailerID3v1 TrailerKind = iota
+
+	// TrailerAPE is APEv1 or APEv2 tag.
+	TrailerAPE
+
+	// TrailerID3v2 is ID3v2.4 tag appended to the end with footer.
+	TrailerID3v2
+)
+
+func (k TrailerKind) String() string {
+	switch k {
+	case TrailerID3v1:
+		return "ID3v1"
+	case TrailerAPE:
+		return "APE"
+	case TrailerID3v2:
+		return "ID3v2"
+	}
+	return fmt.Sprintf("TrailerKind(%d)", int(k))
+}
+
+// Trailer describes a tag found at the end of reader. id3v2 doesn't
+// parse trailers and leaves them in the music part by saving.
+type Trailer struct {
+	Kind TrailerKind
+
+	// Offset is the offset of trailer from the start of reader.
+	Offset int64
+
+	// Size is the size of trailer in bytes.
+	Size int64
+}
+
+// ParseReaderAt parses tag at the start of r of size bytes considering
+// opts and looks for trailers at the end of r (see Tag.Trailers).
+// If there is no tag in r, it will create new one with version ID3v2.4.
+//
+// Unlike ParseReader, it doesn't depend on the position in r, so r can
+// be shared, e.g. memory-mapped file or reader of HTTP range requests.
+// Lazy parsing (see Options.Lazy) and SaveTo/SaveToWriter are supported.
+func ParseReaderAt(r io.ReaderAt, size int64, opts Options) (*Tag, error) {
+	if r == nil {
+		return nil, errors.New("r is nil")
+	}
+
+	tag := NewEmptyTag()
+	if err := tag.parse(context.Background(), io.NewSectionReader(r, 0, size), opts); err != nil {
+		return tag, err
+	}
+
+	trailers, err := findTrailers(r, tag.originalSize, size)
+	if err != nil {
+		return tag, err
+	}
+	tag.trailers = trailers
+	return tag, nil
+}
+
+// Trailers returns tags found at the end of reader by ParseReaderAt
+// sorted by offset.
+func (tag *Tag) Trailers() []Trailer {
+	return tag.trailers
+}
+
+// findTrailers finds trailers in r between start and end.
+func findTrailers(r io.ReaderAt, start, end int64) ([]Trailer, error) {
+	var trailers []Trailer
+
+	// ID3v1 tag is always the last one.
+	t, ok, err := findID3v1(r, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		trailers = append(trailers, t)
+		end = t.Offset
+	}
+
+	// APE and ID3v2 tags can be in any order before ID3v1 tag.
+	for {
+		t, ok, err := findAPE(r, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if t, ok, err = findAppendedID3v2(r, start, end); err != nil {
+				return nil, err
+			}
+		}
+		if !ok {
+			break
+		}
+		trailers = append(trailers, t)
+		end = t.Offset
+	}
+
+	sort.Slice(trailers, func(i, j int) bool {
+		return trailers[i].Offset < trailers[j].Offset
+	})
+	return trailers, nil
+}
+
+// readAt reads len(p) bytes at off in r. ok is false, if there are not
+// enough bytes between start and end.
+func readAt(r io.ReaderAt, p []byte, off, start, end int64) (ok bool, err error) {
+	if off < start || off+int64(len(p)) > end {
+		return false, nil
+	}
+	n, err := r.ReadAt(p, off)
+	if n == len(p) {
+		return true, nil
+	}
+	if err == io.EOF {
+		return false, nil
+	}
+	return false, err
+}
+
+func findID3v1(r io.ReaderAt, start, end int64) (Trailer, bool, error) {
+	buf := make([]byte, len(id3v1EnhancedIdentifier))
+
+	offset := end - id3v1Size
+	ok, err := readAt(r, buf[:len(id3v1Identifier)], offset, start, end)
+	if err != nil || !ok || !bytes.Equal(buf[:len(id3v1Identifier)], id3v1Identifier) {
+		return Trailer{}, false, err
+	}
+	t := Trailer{Kind: TrailerID3v1, Offset: offset, Size: id3v1Size}
+
+	// Enhanced tag is placed before ID3v1 tag.
+	offset -= id3v1EnhancedSize
+	ok, err = readAt(r, buf, offset, start, end)
+	if err != nil {
+		return Trailer{}, false, err
+	}
+	if ok && bytes.Equal(buf, id3v1EnhancedIdentifier) {
+		t.Offset = offset
+		t.Size += id3v1EnhancedSize
+	}
+	return t, true, nil
+}
+
+func findAPE(r io.ReaderAt, start, end int64) (Trailer, bool, error) {
+	footer := make([]byte, apeFooterSize)
+	ok, err := readAt(r, footer, end-apeFooterSize, start, end)
+	if err != nil || !ok || !bytes.Equal(footer[:len(apeIdentifier)], apeIdentifier) {
+		return Trailer{}, false, err
+	}
+
+	// Size includes footer and items, but not header.
+	size := int64(binary.LittleEndian.Uint32(footer[12:16]))
+	if binary.LittleEndian.Uint32(footer[20:24])&apeFlagHeader != 0 {
+		size += apeFooterSize
+	}
+	if size < apeFooterSize || end-size < start {
+		return Trailer{}, false, nil
+	}
+	return Trailer{Kind: TrailerAPE, Offset: end - size, Size: size}, true, nil
+}
+
+func findAppendedID3v2(r io.ReaderAt, start, end int64) (Trailer, bool, error) {
+	footer := make([]byte, tagFooterSize)
+	ok, err := readAt(r, footer, end-tagFooterSize, start, end)
+	if err != nil || !ok || !bytes.Equal(footer[:len(footerIdentifier)], footerIdentifier) {
+		return Trailer{}, false, err
+	}
+
+	// Footer is a copy of tag header with another identifier.
+	framesSize, err := parseSize(footer[6:], true)
+	if err != nil {
+		return Trailer{}, false, nil
+	}
+	size := tagHeaderSize + framesSize + tagFooterSize
+	if end-size < start {
+		return Trailer{}, false, nil
+	}
+	return Trailer{Kind: TrailerID3v2, Offset: end - size, Size: size}, true, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// makeTagWithFooter returns ID3v2.4 tag with title and footer.
+func makeTagWithFooter(t *testing.T, title string) []byte {
+	tag := NewEmptyTag()
+	tag.SetTitle(title)
+	buf := new(bytes.Buffer)
+	if _, err := tag.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	data[5] |= flagFooter
+
+	footer := append([]byte("3DI"), data[3:tagHeaderSize]...)
+	return append(data, footer...)
+}
+
+// makeAPETag returns APEv2 tag with header and items of itemsSize bytes.
+func makeAPETag(itemsSize int) []byte {
+	makeHeader := func(isHeader bool) []byte {
+		header := make([]byte, apeFooterSize)
+		copy(header, apeIdentifier)
+		binary.LittleEndian.PutUint32(header[8:12], 2000)
+		binary.LittleEndian.PutUint32(header[12:16], uint32(itemsSize+apeFooterSize))
+		flags := uint32(apeFlagHeader)
+		if isHeader {
+			flags |= 1 << 29
+		}
+		binary.LittleEndian.PutUint32(header[20:24], flags)
+		return header
+	}
+
+	ape := makeHeader(true)
+	ape = append(ape, make([]byte, itemsSize)...)
+	return append(ape, makeHeader(false)...)
+}
+
+func makeID3v1Tag() []byte {
+	tag := make([]byte, id3v1Size)
+	copy(tag, id3v1Identifier)
+	return tag
+}
+
+func TestParseReaderAtTrailers(t *testing.T) {
+	t.Parallel()
+
+	source := makeSourceWithMusic(t)
+	musicStart := int64(len(source) - len(music))
+	appended := makeTagWithFooter(t, "Appended")
+	ape := makeAPETag(20)
+	id3v1 := makeID3v1Tag()
+
+	data := append([]byte{}, source...)
+	data = append(data, appended...)
+	data = append(data, ape...)
+	data = append(data, id3v1...)
+
+	tag, err := ParseReaderAt(bytes.NewReader(data), int64(len(data)), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tag.Title())
+	}
+
+	appendedOffset := int64(len(source))
+	apeOffset := appendedOffset + int64(len(appended))
+	id3v1Offset := apeOffset + int64(len(ape))
+	expected := []Trailer{
+		{Kind: TrailerID3v2, Offset: appendedOffset, Size: int64(len(appended))},
+		{Kind: TrailerAPE, Offset: apeOffset, Size: int64(len(ape))},
+		{Kind: TrailerID3v1, Offset: id3v1Offset, Size: id3v1Size},
+	}
+	trailers := tag.Trailers()
+	if len(trailers) != len(expected) {
+		t.Fatalf("Expected trailers %v, got %v", expected, trailers)
+	}
+	for i := range expected {
+		if trailers[i] != expected[i] {
+			t.Errorf("Expected trailer %v, got %v", expected[i], trailers[i])
+		}
+	}
+
+	// Trailers must be left in the music part.
+	tag.SetTitle("New title")
+	buf := new(bytes.Buffer)
+	if err := tag.SaveToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	saved := buf.Bytes()
+	if !bytes.HasSuffix(saved, data[musicStart:]) {
+		t.Error("Music part and trailers must be left untouched")
+	}
+}
+
+func TestParseReaderAtWithoutTrailers(t *testing.T) {
+	t.Parallel()
+
+	source := makeSourceWithMusic(t)
+	tag, err := ParseReaderAt(bytes.NewReader(source), int64(len(source)), Options{Parse: true, Lazy: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tag.Title())
+	}
+	if len(tag.Trailers()) != 0 {
+		t.Errorf("Expected no trailers, got %v", tag.Trailers())
+	}
+}
+
+func TestParseReaderAtEnhancedID3v1(t *testing.T) {
+	t.Parallel()
+
+	enhanced := make([]byte, id3v1EnhancedSize)
+	copy(enhanced, id3v1EnhancedIdentifier)
+	data := append(append(append([]byte{}, music...), enhanced...), makeID3v1Tag()...)
+
+	tag, err := ParseReaderAt(bytes.NewReader(data), int64(len(data)), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Trailer{Kind: TrailerID3v1, Offset: int64(len(music)), Size: id3v1EnhancedSize + id3v1Size}
+	if trailers := tag.Trailers(); len(trailers) != 1 || trailers[0] != expected {
+		t.Errorf("Expected trailers %v, got %v", []Trailer{expected}, trailers)
+	}
+}
+
+func TestParseTagWithFooter(t *testing.T) {
+	t.Parallel()
+
+	data := append(makeTagWithFooter(t, "Title"), music...)
+	tag, err := ParseReader(bytes.NewReader(data), parseOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Title() != "Title" {
+		t.Errorf("Expected title %q, got %q", "Title", tag.Title())
+	}
+
+	// Footer must not be written as music.
+	tag.SetTitle("New title")
+	buf := new(bytes.Buffer)
+	if err := tag.SaveToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	testSavedWithMusic(t, buf.Bytes())
+}
@@ -5,25 +5,22 @@
 package id3v2
 
 import (
+	"crypto/sha1"
+	"fmt"
 	"io"
-	"math/rand"
-	"strconv"
-	"time"
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 // UnknownFrame is used for frames, which id3v2 so far doesn't know how to
 // parse and write it. It just contains an unparsed byte body of the frame.
 type UnknownFrame struct {
 	Body []byte
 }
 
+// UniqueIdentifier returns the hash of body, because we don't know
+// the real identifiers of unknown frames. So unknown frames with the same
+// body are considered the same.
 func (uf UnknownFrame) UniqueIdentifier() string {
-	// All unknown frames should have unique identifier, because we don't know their real identifiers.
-	return strconv.Itoa(rand.Int())
+	return fmt.Sprintf("%x", sha1.Sum(uf.Body))
 }
 
 func (uf UnknownFrame) Size() int {
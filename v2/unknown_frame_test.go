@@ -10,10 +10,42 @@ import (
 )
 
 func TestUnknownFramesUniqueIdentifiers(t *testing.T) {
-	uf1, _ := parseUnknownFrame(newBufReader(new(bytes.Buffer)))
-	uf2, _ := parseUnknownFrame(newBufReader(new(bytes.Buffer)))
+	uf1, _ := parseUnknownFrame(newBufReader(bytes.NewReader([]byte("body"))))
+	uf2, _ := parseUnknownFrame(newBufReader(bytes.NewReader([]byte("body"))))
+	uf3, _ := parseUnknownFrame(newBufReader(bytes.NewReader([]byte("other body"))))
 
-	if uf1.UniqueIdentifier() == uf2.UniqueIdentifier() {
-		t.Errorf("Two unknown frame have same unique identifiers, but every unknown frame should have completely unique identifiers.")
+	if uf1.UniqueIdentifier() != uf2.UniqueIdentifier() {
+		t.Errorf("Unknown frames with same body should have same unique identifiers, got %q and %q", uf1.UniqueIdentifier(), uf2.UniqueIdentifier())
 	}
+	if uf1.UniqueIdentifier() == uf3.UniqueIdentifier() {
+		t.Errorf("Unknown frames with different bodies should have different unique identifiers, got %q", uf1.UniqueIdentifier())
+	}
+}
+
+func TestUnknownFramesDeduplication(t *testing.T) {
+	tag := NewEmptyTag()
+	tag.AddFrame("WXXX", UnknownFrame{Body: []byte("https://example.com")})
+	tag.AddFrame("WXXX", UnknownFrame{Body: []byte("https://example.com")})
+	tag.AddFrame("WXXX", UnknownFrame{Body: []byte("https://example.org")})
+
+	if got := len(tag.GetFrames("WXXX")); got != 2 {
+		t.Errorf("Expected 2 unknown frames, got %v", got)
+	}
+}
+
+func TestUnknownFramesAddWithoutHashing(t *testing.T) {
+	s := newSequence()
+	for i := 0; i < 100; i++ {
+		s.AddFrame(UnknownFrame{Body: []byte{byte(i)}})
+	}
+
+	// Bodies must be compared without allocation of identifiers.
+	var uf Framer = UnknownFrame{Body: []byte{99}}
+	allocs := testing.AllocsPerRun(100, func() {
+		s.AddFrame(uf)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+	testSequenceCount(t, s, 100)
 }
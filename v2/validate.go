@@ -0,0 +1,286 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ViolationKind is the kind of violation of ID3v2 specification.
+type ViolationKind int
+
+const (
+	// ViolationDuplicateFrame means, that frame, which can be in tag
+	// only once, is in tag more than once. For APIC frames it means,
+	// that there is more than one picture of type PTFileIcon or PTOtherFileIcon.
+	ViolationDuplicateFrame ViolationKind = iota
+
+	// ViolationInvalidEncoding means, that frame has encoding, which is not
+	// allowed in tag version, e.g. UTF-8 in ID3v2.3. It can be fixed by
+	// reencoding with UTF-16.
+	ViolationInvalidEncoding
+
+	// ViolationInvalidLanguage means, that language of frame doesn't
+	// consist of three letters. It can be fixed, if language is blank,
+	// by setting it to "XXX" (unknown language).
+	ViolationInvalidLanguage
+
+	// ViolationNonNumericText means, that text of frame like TLEN or TBPM
+	// is not a numeric string. It can be fixed, if the text is numeric
+	// after trimming of spaces.
+	ViolationNonNumericText
+
+	// ViolationSizeOverflow means, that size of frame or tag is greater
+	// than allowed in tag version.
+	ViolationSizeOverflow
+
+	// ViolationUnsupportedFrame means, that frame is not defined in tag
+	// version, e.g. TDRC in ID3v2.3. ConvertVersion converts such frames,
+	// if they're added before conversion.
+	ViolationUnsupportedFrame
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case ViolationDuplicateFrame:
+		return "frame must be unique"
+	case ViolationInvalidEncoding:
+		return "encoding is not allowed in tag version"
+	case ViolationInvalidLanguage:
+		return "language must consist of three letters"
+	case ViolationNonNumericText:
+		return "text must be numeric"
+	case ViolationSizeOverflow:
+		return "size is greater than allowed in tag version"
+	case ViolationUnsupportedFrame:
+		return "frame is not defined in tag version"
+	}
+	return fmt.Sprintf("ViolationKind(%d)", int(k))
+}
+
+// Violation describes a violation of ID3v2 specification found in tag.
+type Violation struct {
+	// FrameID is the ID of frame. It's blank, if violation is in the whole tag.
+	FrameID string
+
+	Kind ViolationKind
+}
+
+func (v Violation) String() string {
+	if v.FrameID == "" {
+		return "tag: " + v.Kind.String()
+	}
+	return v.FrameID + ": " + v.Kind.String()
+}
+
+// singleInstanceIDs are IDs of frames, which can be in tag only once,
+// but are kept in sequences (see mustFrameBeInSequence).
+var singleInstanceIDs = []string{
+	"ASPI", "EQUA", "ETCO", "MCDI", "MLLT", "OWNE", "PCNT", "POSS",
+	"RBUF", "RVRB", "SEEK", "SYTC",
+}
+
+// numericTextIDs are IDs of text frames, which must contain numeric string.
+var numericTextIDs = []string{"TBPM", "TDLY", "TLEN", "TSIZ"}
+
+// Validate checks tag against ID3v2 specification of its version and
+// returns found violations sorted by frame ID. Violations, which can be
+// fixed safely, are fixed by Fix.
+func (tag *Tag) Validate() []Violation {
+	var violations []Violation
+	add := func(id string, kind ViolationKind) {
+		violations = append(violations, Violation{FrameID: id, Kind: kind})
+	}
+
+	maxFrameSize := int64(synchUnsafeMaxSize)
+	if tag.version == 4 {
+		maxFrameSize = synchSafeMaxSize
+	}
+
+	tag.iterateOverAllFrames(func(id string, f Framer) error {
+		if int64(f.Size()) > maxFrameSize {
+			add(id, ViolationSizeOverflow)
+		}
+		for _, encoding := range frameEncodings(f) {
+			if !isEncodingAllowed(encoding, tag.version) {
+				add(id, ViolationInvalidEncoding)
+				break
+			}
+		}
+		if language, ok := frameLanguage(f); ok && len(language) != 3 {
+			add(id, ViolationInvalidLanguage)
+		}
+		if tf, ok := f.(TextFrame); ok && containsString(numericTextIDs, id) && !isNumeric(tf.Text) {
+			add(id, ViolationNonNumericText)
+		}
+		return nil
+	})
+
+	for _, id := range singleInstanceIDs {
+		if len(tag.GetFrames(id)) > 1 {
+			add(id, ViolationDuplicateFrame)
+		}
+	}
+	if hasDuplicateIcons(tag.GetFrames(tag.CommonID("Attached picture"))) {
+		add(tag.CommonID("Attached picture"), ViolationDuplicateFrame)
+	}
+
+	unsupportedIDs := append([]string{"TDOR", "TDRC", "TIPL", "TMCL"}, v24OnlyIDs...)
+	if tag.version == 4 {
+		unsupportedIDs = append([]string{"IPLS", "TDAT", "TIME", "TORY", "TRDA", "TYER"}, v23OnlyIDs...)
+	}
+	for _, id := range tag.existingIDs(unsupportedIDs...) {
+		add(id, ViolationUnsupportedFrame)
+	}
+
+	if tag.Size()-tagHeaderSize > synchSafeMaxSize {
+		add("", ViolationSizeOverflow)
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].FrameID < violations[j].FrameID
+	})
+	return violations
+}
+
+// Fix fixes violations found by Validate, which can be fixed safely
+// without losing of information, and returns the rest of violations.
+func (tag *Tag) Fix() []Violation {
+	tag.loadAllLazyFrames()
+	tag.convertFrames(tag.fixFrame)
+	return tag.Validate()
+}
+
+func (tag *Tag) fixFrame(id string, f Framer) Framer {
+	switch f := f.(type) {
+	case TextFrame:
+		return tag.fixTextFrame(id, f)
+	case CommentFrame:
+		f.Encoding = tag.fixEncoding(f.Encoding)
+		if f.Language == "" {
+			f.Language = "XXX"
+		}
+		return f
+	case PictureFrame:
+		f.Encoding = tag.fixEncoding(f.Encoding)
+		return f
+	case UnsynchronisedLyricsFrame:
+		f.Encoding = tag.fixEncoding(f.Encoding)
+		if f.Language == "" {
+			f.Language = "XXX"
+		}
+		return f
+	case UserDefinedTextFrame:
+		f.Encoding = tag.fixEncoding(f.Encoding)
+		return f
+	case ChapterFrame:
+		if f.Title != nil {
+			title := tag.fixTextFrame("TIT2", *f.Title)
+			f.Title = &title
+		}
+		if f.Description != nil {
+			description := tag.fixTextFrame("TIT3", *f.Description)
+			f.Description = &description
+		}
+		return f
+	}
+	return f
+}
+
+func (tag *Tag) fixTextFrame(id string, tf TextFrame) TextFrame {
+	tf.Encoding = tag.fixEncoding(tf.Encoding)
+	if containsString(numericTextIDs, id) {
+		if trimmed := strings.TrimSpace(tf.Text); isNumeric(trimmed) {
+			tf.Text = trimmed
+		}
+	}
+	return tf
+}
+
+func (tag *Tag) fixEncoding(encoding Encoding) Encoding {
+	if tag.version == 3 {
+		return v23Encoding(encoding)
+	}
+	return encoding
+}
+
+// isEncodingAllowed checks if encoding is defined in ID3v2 version.
+func isEncodingAllowed(encoding Encoding, version byte) bool {
+	if version == 3 {
+		return encoding.Equals(EncodingISO) || encoding.Equals(EncodingUTF16)
+	}
+	return true
+}
+
+// frameEncodings returns encodings used in f.
+func frameEncodings(f Framer) []Encoding {
+	switch f := f.(type) {
+	case TextFrame:
+		return []Encoding{f.Encoding}
+	case CommentFrame:
+		return []Encoding{f.Encoding}
+	case PictureFrame:
+		return []Encoding{f.Encoding}
+	case UnsynchronisedLyricsFrame:
+		return []Encoding{f.Encoding}
+	case UserDefinedTextFrame:
+		return []Encoding{f.Encoding}
+	case ChapterFrame:
+		var encodings []Encoding
+		if f.Title != nil {
+			encodings = append(encodings, f.Title.Encoding)
+		}
+		if f.Description != nil {
+			encodings = append(encodings, f.Description.Encoding)
+		}
+		return encodings
+	}
+	return nil
+}
+
+// frameLanguage returns language of f, if f has it.
+func frameLanguage(f Framer) (string, bool) {
+	switch f := f.(type) {
+	case CommentFrame:
+		return f.Language, true
+	case UnsynchronisedLyricsFrame:
+		return f.Language, true
+	}
+	return "", false
+}
+
+// hasDuplicateIcons checks if there is more than one picture of type
+// PTFileIcon or PTOtherFileIcon in pictures.
+func hasDuplicateIcons(pictures []Framer) bool {
+	var icons, otherIcons int
+	for _, f := range pictures {
+		pf, ok := f.(PictureFrame)
+		if !ok {
+			continue
+		}
+		switch pf.PictureType {
+		case PTFileIcon:
+			icons++
+		case PTOtherFileIcon:
+			otherIcons++
+		}
+	}
+	return icons > 1 || otherIcons > 1
+}
+
+// isNumeric checks if s consists only of digits.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetVersion(3)
+	tag.AddTextFrame("TIT2", EncodingUTF8, "Title")
+	tag.AddTextFrame("TLEN", EncodingISO, " 1000 ")
+	tag.AddTextFrame("TBPM", EncodingISO, "fast")
+	tag.AddTextFrame("TDRC", EncodingISO, "2020")
+	tag.AddCommentFrame(CommentFrame{Encoding: EncodingISO, Description: "Description", Text: "Text"})
+	tag.AddFrame("PCNT", UnknownFrame{Body: []byte{0, 0, 0, 1}})
+	tag.AddFrame("PCNT", UnknownFrame{Body: []byte{0, 0, 0, 2}})
+	tag.AddAttachedPicture(PictureFrame{Encoding: EncodingISO, PictureType: PTFileIcon, Description: "Icon 1"})
+	tag.AddAttachedPicture(PictureFrame{Encoding: EncodingISO, PictureType: PTFileIcon, Description: "Icon 2"})
+
+	expected := []Violation{
+		{"APIC", ViolationDuplicateFrame},
+		{"COMM", ViolationInvalidLanguage},
+		{"PCNT", ViolationDuplicateFrame},
+		{"TBPM", ViolationNonNumericText},
+		{"TDRC", ViolationUnsupportedFrame},
+		{"TIT2", ViolationInvalidEncoding},
+		{"TLEN", ViolationNonNumericText},
+	}
+	if got := tag.Validate(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected violations %v, got %v", expected, got)
+	}
+
+	expected = []Violation{
+		{"APIC", ViolationDuplicateFrame},
+		{"PCNT", ViolationDuplicateFrame},
+		{"TBPM", ViolationNonNumericText},
+		{"TDRC", ViolationUnsupportedFrame},
+	}
+	if got := tag.Fix(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected violations after fix %v, got %v", expected, got)
+	}
+	if got := tag.GetTextFrame("TIT2").Encoding; !got.Equals(EncodingUTF16) {
+		t.Errorf("Expected encoding %v, got %v", EncodingUTF16, got)
+	}
+	if got := tag.GetTextFrame("TLEN").Text; got != "1000" {
+		t.Errorf("Expected TLEN %q, got %q", "1000", got)
+	}
+	if got := tag.GetLastFrame("COMM").(CommentFrame).Language; got != "XXX" {
+		t.Errorf("Expected language %q, got %q", "XXX", got)
+	}
+}
+
+func TestValidateValidTag(t *testing.T) {
+	t.Parallel()
+
+	tag := NewEmptyTag()
+	tag.SetTitle("Title")
+	tag.AddTextFrame("TLEN", EncodingUTF8, "1000")
+	tag.AddCommentFrame(engComm)
+	tag.AddAttachedPicture(frontCover)
+	tag.AddAttachedPicture(backCover)
+
+	if got := tag.Validate(); len(got) != 0 {
+		t.Errorf("Expected no violations, got %v", got)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidFrameHeader is returned by parsing in strict mode,
+// if frame header has invalid ID or blank size.
+var ErrInvalidFrameHeader = errors.New("invalid frame header")
+
+// ErrJunkInPadding is returned by parsing in strict mode,
+// if there are non-zero bytes in padding.
+var ErrJunkInPadding = errors.New("non-zero bytes in padding")
+
+// WarningKind is the kind of problem found by parsing.
+type WarningKind int
+
+const (
+	// WarningInvalidFrameHeader means, that frame has blank size
+	// or invalid ID. The rest of tag is skipped, if the ID is blank.
+	WarningInvalidFrameHeader WarningKind = iota
+
+	// WarningInvalidSize means, that frame size has invalid format.
+	// The rest of tag is skipped.
+	WarningInvalidSize
+
+	// WarningBodyOverflow means, that frame goes over tag area.
+	// The rest of tag is skipped.
+	WarningBodyOverflow
+
+	// WarningTruncated means, that reader ends before tag area.
+	WarningTruncated
+
+	// WarningNonSynchSafeSize means, that ID3v2.4 tag has non-synchsafe
+	// frame sizes, like tags written by old versions of iTunes.
+	// Frame sizes are read as non-synchsafe from this frame to the end of tag.
+	WarningNonSynchSafeSize
+
+	// WarningJunkInPadding means, that there are non-zero bytes in padding.
+	// See Padding.HasJunk.
+	WarningJunkInPadding
+
+	// WarningCRCMismatch means, that CRC-32 in extended header doesn't
+	// match CRC-32 of tag data. Frames are parsed anyway.
+	WarningCRCMismatch
+)
+
+func (k WarningKind) String() string {
+	switch k {
+	case WarningInvalidFrameHeader:
+		return "invalid frame header"
+	case WarningInvalidSize:
+		return "invalid frame size"
+	case WarningBodyOverflow:
+		return "frame went over tag area"
+	case WarningTruncated:
+		return "tag is truncated"
+	case WarningNonSynchSafeSize:
+		return "non-synchsafe frame size in ID3v2.4 tag"
+	case WarningJunkInPadding:
+		return "non-zero bytes in padding"
+	case WarningCRCMismatch:
+		return "CRC-32 mismatch"
+	}
+	return fmt.Sprintf("WarningKind(%d)", int(k))
+}
+
+// err returns the error, which is returned by parsing in strict mode
+// instead of warning of this kind.
+func (k WarningKind) err() error {
+	switch k {
+	case WarningInvalidFrameHeader:
+		return ErrInvalidFrameHeader
+	case WarningInvalidSize, WarningNonSynchSafeSize:
+		return ErrInvalidSizeFormat
+	case WarningBodyOverflow:
+		return ErrBodyOverflow
+	case WarningTruncated:
+		return io.ErrUnexpectedEOF
+	case WarningJunkInPadding:
+		return ErrJunkInPadding
+	case WarningCRCMismatch:
+		return ErrCRCMismatch
+	}
+	return errors.New(k.String())
+}
+
+// Warning describes a violation of ID3v2 specification, which was found
+// by parsing in lenient mode (see Options.Lenient) and recovered from.
+type Warning struct {
+	// Offset is the offset of frame header from the start of tag.
+	Offset int64
+
+	// FrameID is the ID of frame. It can be blank, if it's unknown.
+	FrameID string
+
+	Kind WarningKind
+}
+
+func (w Warning) String() string {
+	if w.FrameID == "" {
+		return fmt.Sprintf("%v at offset %d", w.Kind, w.Offset)
+	}
+	return fmt.Sprintf("%v in frame %q at offset %d", w.Kind, w.FrameID, w.Offset)
+}
+
+// Warnings returns problems found by parsing of tag in lenient mode.
+func (tag *Tag) Warnings() []Warning {
+	return tag.warnings
+}
+
+// warn records warning of kind, if tag is parsed in lenient mode,
+// or returns the corresponding error, if tag is parsed in strict mode.
+// By default only WarningBodyOverflow is returned as error and other
+// problems are recovered from silently.
+func (tag *Tag) warn(opts Options, offset int64, id string, kind WarningKind) error {
+	if opts.Strict || (!opts.Lenient && kind == WarningBodyOverflow) {
+		return kind.err()
+	}
+	if opts.Lenient {
+		tag.warnings = append(tag.warnings, Warning{Offset: offset, FrameID: id, Kind: kind})
+	}
+	return nil
+}
+
+// isValidFrameID checks if id consists of capital letters and digits.
+func isValidFrameID(id string) bool {
+	if len(id) != 4 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// validFrameID returns id as string, if it's valid, otherwise "".
+func validFrameID(id []byte) string {
+	if isValidFrameID(string(id)) {
+		return string(id)
+	}
+	return ""
+}
+
+// isBlank checks if all bytes of b are zero.
+func isBlank(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+// Copyright 2016 Albert Nigmatzianov. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package id3v2
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// makeTagWithTitle writes ID3v2.4 tag with size of frames framesSize,
+// valid TIT2 frame and then rest.
+func makeTagWithTitle(t *testing.T, framesSize uint, rest []byte) []byte {
+	buf := new(bytes.Buffer)
+	bw := newBufWriter(buf)
+
+	writeTagHeader(bw, framesSize, 4)
+	bw.Write([]byte{0x54, 0x49, 0x54, 0x32, 00, 00, 00, 06, 00, 00, 03}) // header and encoding
+	bw.WriteString("Title")
+	bw.Write(rest)
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		data     []byte
+		warnings []Warning
+		err      error
+	}{
+		{
+			name: "padding",
+			data: makeTagWithTitle(t, 16+20, make([]byte, 20)),
+		},
+		{
+			name:     "invalid size",
+			data:     makeTagWithTitle(t, 16+10, []byte{0x54, 0x49, 0x54, 0x32, 255, 255, 255, 255, 00, 00}),
+			warnings: []Warning{{Offset: 26, FrameID: "TIT2", Kind: WarningInvalidSize}},
+			err:      ErrInvalidSizeFormat,
+		},
+		{
+			name:     "blank size",
+			data:     makeTagWithTitle(t, 16+10, []byte{0x54, 0x49, 0x54, 0x32, 0, 0, 0, 0, 00, 00}),
+			warnings: []Warning{{Offset: 26, FrameID: "TIT2", Kind: WarningInvalidFrameHeader}},
+			err:      ErrInvalidFrameHeader,
+		},
+		{
+			name:     "junk in padding",
+			data:     makeTagWithTitle(t, 16+12, []byte{0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 1, 1}),
+			warnings: []Warning{{Offset: 26, Kind: WarningJunkInPadding}},
+			err:      ErrJunkInPadding,
+		},
+		{
+			name:     "junk after first bytes of padding",
+			data:     makeTagWithTitle(t, 16+20, append(make([]byte, 15), 'J', 0, 0, 0, 0)),
+			warnings: []Warning{{Offset: 26, Kind: WarningJunkInPadding}},
+			err:      ErrJunkInPadding,
+		},
+		{
+			name:     "body overflow",
+			data:     makeTagWithTitle(t, 16+12, []byte{0x54, 0x50, 0x45, 0x31, 0, 0, 0, 6, 0, 0, 3, 'A'}),
+			warnings: []Warning{{Offset: 26, FrameID: "TPE1", Kind: WarningBodyOverflow}},
+			err:      ErrBodyOverflow,
+		},
+		{
+			name:     "truncated tag",
+			data:     makeTagWithTitle(t, 16+100, nil),
+			warnings: []Warning{{Offset: 26, Kind: WarningTruncated}},
+			err:      io.ErrUnexpectedEOF,
+		},
+		{
+			name:     "truncated body",
+			data:     makeTagWithTitle(t, 16+100, []byte{0x54, 0x50, 0x45, 0x31, 0, 0, 0, 6, 0, 0, 3, 'A'}),
+			warnings: []Warning{{Offset: 26, FrameID: "TPE1", Kind: WarningTruncated}},
+			err:      io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tc := range testCases {
+		tag, err := ParseReader(bytes.NewReader(tc.data), Options{Parse: true, Lenient: true})
+		if err != nil {
+			t.Errorf("%v: unexpected error in lenient mode: %v", tc.name, err)
+			continue
+		}
+		if tag.Title() != "Title" {
+			t.Errorf("%v: expected title %q, got %q", tc.name, "Title", tag.Title())
+		}
+		if !reflect.DeepEqual(tag.Warnings(), tc.warnings) {
+			t.Errorf("%v: expected warnings %v, got %v", tc.name, tc.warnings, tag.Warnings())
+		}
+
+		_, err = ParseReader(bytes.NewReader(tc.data), Options{Parse: true, Strict: true})
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%v: expected error %v in strict mode, got %v", tc.name, tc.err, err)
+		}
+
+		// By default only body overflow is returned as error
+		// and warnings are not recorded.
+		tag, err = ParseReader(bytes.NewReader(tc.data), parseOpts)
+		if tc.err == ErrBodyOverflow {
+			if !errors.Is(err, ErrBodyOverflow) {
+				t.Errorf("%v: expected error %v by default, got %v", tc.name, ErrBodyOverflow, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error by default: %v", tc.name, err)
+			continue
+		}
+		if len(tag.Warnings()) != 0 {
+			t.Errorf("%v: expected no warnings by default, got %v", tc.name, tag.Warnings())
+		}
+	}
+}
+
+func TestParseWarningsOfValidTag(t *testing.T) {
+	t.Parallel()
+
+	tag, err := Open(mp3Path, Options{Parse: true, Strict: true})
+	if err != nil {
+		t.Fatal("Error while opening mp3 file:", err)
+	}
+	defer tag.Close()
+
+	if len(tag.Warnings()) != 0 {
+		t.Errorf("Expected no warnings, got %v", tag.Warnings())
+	}
+}